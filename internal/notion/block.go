@@ -1,17 +1,28 @@
 package notion
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 )
 
 type BlockRequest struct {
-	method  string
-	blockId string
+	method   string
+	blockId  string
+	children []Block
+	after    string
+	body     map[string]any
+	cursor
 }
 
+// maxAppendChildren is how many blocks Notion accepts in one append request.
+const maxAppendChildren = 100
+
 type BlockResponse struct {
 	Object string `json:"object"`
 	BlockGetResponse
@@ -19,24 +30,55 @@ type BlockResponse struct {
 }
 
 type BlockGetResponse struct {
-	Object           string     `json:"object"`
-	ID               string     `json:"id"`
-	Parent           PageParent `json:"parent"`
-	Type             string     `json:"type"`
-	CreatedTime      time.Time  `json:"created_time"`
-	CreatedBy        User       `json:"created_by"`
-	LastEditedTime   time.Time  `json:"last_edited_time"`
-	LastEditedBy     User       `json:"last_edited_by"`
-	Archived         bool       `json:"archived"`
-	InTrash          bool       `json:"in_trash"`
-	HasChildren      bool       `json:"has_children"`
-	BulletedListItem *Paragraph `json:"bulleted_list_item,omitempty"`
-	Paragraph        *Paragraph `json:"paragraph,omitempty"`
-	Heading1         *Paragraph `json:"heading_1,omitempty"`
-	Heading2         *Paragraph `json:"heading_2,omitempty"`
-	Heading3         *Paragraph `json:"heading_3,omitempty"`
+	Object           string           `json:"object"`
+	ID               string           `json:"id"`
+	Parent           PageParent       `json:"parent"`
+	Type             string           `json:"type"`
+	CreatedTime      time.Time        `json:"created_time"`
+	CreatedBy        User             `json:"created_by"`
+	LastEditedTime   time.Time        `json:"last_edited_time"`
+	LastEditedBy     User             `json:"last_edited_by"`
+	Archived         bool             `json:"archived"`
+	InTrash          bool             `json:"in_trash"`
+	HasChildren      bool             `json:"has_children"`
+	Audio            *FileBlock       `json:"audio,omitempty"`
+	Bookmark         *Bookmark        `json:"bookmark,omitempty"`
+	Breadcrumb       *Breadcrumb      `json:"breadcrumb,omitempty"`
+	BulletedListItem *Paragraph       `json:"bulleted_list_item,omitempty"`
+	Callout          *Callout         `json:"callout,omitempty"`
+	ChildDatabase    *ChildDatabase   `json:"child_database,omitempty"`
+	ChildPage        *ChildPage       `json:"child_page,omitempty"`
+	Code             *Code            `json:"code,omitempty"`
+	Column           *Column          `json:"column,omitempty"`
+	ColumnList       *ColumnList      `json:"column_list,omitempty"`
+	Divider          *Divider         `json:"divider,omitempty"`
+	Embed            *Embed           `json:"embed,omitempty"`
+	Equation         *Equation        `json:"equation,omitempty"`
+	File             *FileBlock       `json:"file,omitempty"`
+	Heading1         *Heading         `json:"heading_1,omitempty"`
+	Heading2         *Heading         `json:"heading_2,omitempty"`
+	Heading3         *Heading         `json:"heading_3,omitempty"`
+	Image            *FileBlock       `json:"image,omitempty"`
+	LinkPreview      *LinkPreview     `json:"link_preview,omitempty"`
+	LinkToPage       *LinkToPage      `json:"link_to_page,omitempty"`
+	NumberedListItem *Paragraph       `json:"numbered_list_item,omitempty"`
+	Paragraph        *Paragraph       `json:"paragraph,omitempty"`
+	PDF              *FileBlock       `json:"pdf,omitempty"`
+	Quote            *Paragraph       `json:"quote,omitempty"`
+	SyncedBlock      *SyncedBlock     `json:"synced_block,omitempty"`
+	Table            *Table           `json:"table,omitempty"`
+	TableOfContents  *TableOfContents `json:"table_of_contents,omitempty"`
+	TableRow         *TableRow        `json:"table_row,omitempty"`
+	Template         *Paragraph       `json:"template,omitempty"`
+	ToDo             *ToDo            `json:"to_do,omitempty"`
+	Toggle           *Paragraph       `json:"toggle,omitempty"`
+	Video            *FileBlock       `json:"video,omitempty"`
+	// Children is only filled in by BlockTree.
+	Children []BlockResponse `json:"children,omitempty"`
 }
 
+// Paragraph is the content of paragraph, bulleted_list_item,
+// numbered_list_item, quote, toggle and template blocks.
 type Paragraph struct {
 	RichText []RichText             `json:"rich_text"`
 	Color    string                 `json:"color"`
@@ -45,6 +87,7 @@ type Paragraph struct {
 
 type BlockChildrenResponse struct {
 	Results []BlockResponse `json:"results"`
+	Pagination
 }
 
 func Blocks(blockId string) *BlockRequest {
@@ -59,9 +102,123 @@ func (pr *BlockRequest) Query() *BlockRequest {
 	return pr
 }
 
+// Append adds children to the end of the block or page, or after the block
+// given to After. More than 100 children are sent in several requests.
+func (pr *BlockRequest) Append(children ...Block) *BlockRequest {
+	pr.method = "APPEND"
+	pr.children = append(pr.children, children...)
+	return pr
+}
+
+// After inserts appended children after the sibling block with this ID
+// instead of at the end.
+func (pr *BlockRequest) After(blockId string) *BlockRequest {
+	pr.after = blockId
+	return pr
+}
+
+// Update replaces the block's content. block must be of the same type as
+// the existing block; its children are ignored.
+func (pr *BlockRequest) Update(block Block) *BlockRequest {
+	pr.method = "UPDATE"
+	pr.body = map[string]any{block.Type: blockContent(block)}
+	return pr
+}
+
+// Delete moves the block to the trash.
+func (pr *BlockRequest) Delete() *BlockRequest {
+	pr.method = "DELETE"
+	return pr
+}
+
+// StartCursor resumes a children query from the next_cursor of a previous
+// page.
+func (pr *BlockRequest) StartCursor(startCursor string) *BlockRequest {
+	pr.startCursor = startCursor
+	return pr
+}
+
+// PageSize sets the number of children per page (Notion allows at most 100).
+func (pr *BlockRequest) PageSize(pageSize int) *BlockRequest {
+	pr.pageSize = pageSize
+	return pr
+}
+
+// All makes Fetch follow next_cursor until every child block has been read,
+// returning the combined results.
+func (pr *BlockRequest) All() *BlockRequest {
+	pr.all = true
+	return pr
+}
+
 func (pr *BlockRequest) Fetch(c *Client) (*BlockResponse, error) {
+	return pr.FetchContext(context.Background(), c)
+}
+
+// FetchContext is Fetch with a context that cancels the Notion call, including
+// any rate limit wait or retry backoff.
+func (pr *BlockRequest) FetchContext(ctx context.Context, c *Client) (*BlockResponse, error) {
+	if pr.method == "APPEND" {
+		return pr.appendChildren(ctx, c)
+	}
+
+	blockResp, err := pr.fetchPage(ctx, c, pr.startCursor)
+	if err != nil {
+		return nil, err
+	}
+	if pr.method != "CHILDREN" || !pr.all {
+		return blockResp, nil
+	}
+
+	for {
+		next, ok := blockResp.Pagination.next()
+		if !ok {
+			return blockResp, nil
+		}
+		page, err := pr.fetchPage(ctx, c, next)
+		if err != nil {
+			return nil, err
+		}
+		blockResp.Results = append(blockResp.Results, page.Results...)
+		blockResp.Pagination = page.Pagination
+	}
+}
+
+// appendChildren sends the children in batches, each placed after the last
+// block created by the previous one.
+func (pr *BlockRequest) appendChildren(ctx context.Context, c *Client) (*BlockResponse, error) {
+	var blockResp *BlockResponse
+	after := pr.after
+	children := pr.children
+	for {
+		batch := children[:min(len(children), maxAppendChildren)]
+		children = children[len(batch):]
+
+		body := map[string]any{"children": emptyIfNil(batch)}
+		if after != "" {
+			body["after"] = after
+		}
+		page, err := pr.send(ctx, c, http.MethodPatch, "/children", nil, body)
+		if err != nil {
+			return nil, err
+		}
+		if blockResp == nil {
+			blockResp = page
+		} else {
+			blockResp.Results = append(blockResp.Results, page.Results...)
+		}
+
+		if len(children) == 0 || len(page.Results) == 0 {
+			return blockResp, nil
+		}
+		after = page.Results[len(page.Results)-1].ID
+	}
+}
+
+func (pr *BlockRequest) fetchPage(ctx context.Context, c *Client, startCursor string) (*BlockResponse, error) {
 	var method string
 	var suffix string
+	query := url.Values{}
 
 	switch pr.method {
 	case "GET":
@@ -70,25 +227,47 @@ func (pr *BlockRequest) Fetch(c *Client) (*BlockResponse, error) {
 	case "CHILDREN":
 		method = http.MethodGet
 		suffix = "/children"
+		if startCursor != "" {
+			query.Set("start_cursor", startCursor)
+		}
+		if pr.pageSize > 0 {
+			query.Set("page_size", strconv.Itoa(pr.pageSize))
+		}
+	case "UPDATE":
+		method = http.MethodPatch
+		suffix = ""
+	case "DELETE":
+		method = http.MethodDelete
+		suffix = ""
 	default:
 		method = http.MethodGet
 		suffix = ""
 	}
 
-	url := c.buildURL("blocks/" + pr.blockId + suffix)
-	fmt.Println(url)
-	req, err := http.NewRequest(method, url, nil)
-	if err != nil {
-		return nil, err
+	return pr.send(ctx, c, method, suffix, query, pr.body)
+}
+
+func (pr *BlockRequest) send(ctx context.Context, c *Client, method string, suffix string, query url.Values, body map[string]any) (*BlockResponse, error) {
+	var reqBody io.Reader
+	if body != nil {
+		jsonBytes, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(jsonBytes)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	reqURL := c.buildURL("blocks/" + pr.blockId + suffix)
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+
 	var pageResp BlockResponse
-	err = json.NewDecoder(resp.Body).Decode(&pageResp)
+	err = c.do(req, &pageResp)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,124 @@
+package notion
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	DefaultTreeDepth       = 8
+	DefaultTreeConcurrency = 3
+)
+
+// BlockTreeRequest fetches a block's children and, recursively, the children
+// of every block with HasChildren set, filling in BlockGetResponse.Children.
+// Child pages and databases are not descended into.
+type BlockTreeRequest struct {
+	blockId     string
+	maxDepth    int
+	concurrency int
+}
+
+func BlockTree(blockId string) *BlockTreeRequest {
+	return &BlockTreeRequest{
+		blockId:     blockId,
+		maxDepth:    DefaultTreeDepth,
+		concurrency: DefaultTreeConcurrency,
+	}
+}
+
+// MaxDepth limits how many levels are fetched; 1 fetches only the direct
+// children, like Blocks(id).Query().All().
+func (tr *BlockTreeRequest) MaxDepth(depth int) *BlockTreeRequest {
+	tr.maxDepth = max(depth, 1)
+	return tr
+}
+
+// Concurrency limits how many children requests are in flight at once. The
+// client's rate limiter still applies on top of this.
+func (tr *BlockTreeRequest) Concurrency(workers int) *BlockTreeRequest {
+	tr.concurrency = max(workers, 1)
+	return tr
+}
+
+func (tr *BlockTreeRequest) Fetch(c *Client) (*BlockResponse, error) {
+	return tr.FetchContext(context.Background(), c)
+}
+
+func (tr *BlockTreeRequest) FetchContext(ctx context.Context, c *Client) (*BlockResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	walk := &treeWalk{
+		client:   c,
+		maxDepth: tr.maxDepth,
+		sem:      make(chan struct{}, tr.concurrency),
+		cancel:   cancel,
+	}
+	root, err := walk.children(ctx, tr.blockId)
+	if err != nil {
+		return nil, err
+	}
+	walk.expand(ctx, root.Results, 1)
+	walk.wg.Wait()
+
+	if walk.err != nil {
+		return nil, walk.err
+	}
+	return root, nil
+}
+
+type treeWalk struct {
+	client   *Client
+	maxDepth int
+	sem      chan struct{}
+	wg       sync.WaitGroup
+	once     sync.Once
+	err      error
+	cancel   context.CancelFunc
+}
+
+// expand fetches the children of blocks in the background. Each goroutine
+// only writes to its own block, so the slices need no locking.
+func (w *treeWalk) expand(ctx context.Context, blocks []BlockResponse, depth int) {
+	if depth >= w.maxDepth {
+		return
+	}
+	for i := range blocks {
+		block := &blocks[i]
+		if !block.HasChildren || block.Type == "child_page" || block.Type == "child_database" {
+			continue
+		}
+
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			resp, err := w.children(ctx, block.ID)
+			if err != nil {
+				w.fail(err)
+				return
+			}
+			block.Children = resp.Results
+			w.expand(ctx, block.Children, depth+1)
+		}()
+	}
+}
+
+func (w *treeWalk) children(ctx context.Context, blockId string) (*BlockResponse, error) {
+	select {
+	case w.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-w.sem }()
+
+	return Blocks(blockId).Query().All().FetchContext(ctx, w.client)
+}
+
+// fail keeps the first error and stops the remaining fetches.
+func (w *treeWalk) fail(err error) {
+	w.once.Do(func() {
+		w.err = err
+		w.cancel()
+	})
+}
@@ -0,0 +1,104 @@
+package notion
+
+// Heading is the content of heading_1, heading_2 and heading_3 blocks.
+// Toggleable headings keep their contents as children.
+type Heading struct {
+	RichText     []RichText `json:"rich_text"`
+	Color        string     `json:"color"`
+	IsToggleable bool       `json:"is_toggleable"`
+}
+
+type ToDo struct {
+	RichText []RichText `json:"rich_text"`
+	Checked  bool       `json:"checked"`
+	Color    string     `json:"color"`
+}
+
+type Callout struct {
+	RichText []RichText  `json:"rich_text"`
+	Icon     *FileObject `json:"icon,omitempty"`
+	Color    string      `json:"color"`
+}
+
+type Code struct {
+	RichText []RichText `json:"rich_text"`
+	Caption  []RichText `json:"caption"`
+	Language string     `json:"language"`
+}
+
+// FileBlock is the content of image, video, audio, file and pdf blocks.
+type FileBlock struct {
+	FileObject
+	Caption []RichText `json:"caption"`
+}
+
+type Bookmark struct {
+	URL     string     `json:"url"`
+	Caption []RichText `json:"caption"`
+}
+
+type Embed struct {
+	URL string `json:"url"`
+}
+
+type LinkPreview struct {
+	URL string `json:"url"`
+}
+
+// Equation holds a KaTeX expression.
+type Equation struct {
+	Expression string `json:"expression"`
+}
+
+type Divider struct{}
+
+type Breadcrumb struct{}
+
+type TableOfContents struct {
+	Color string `json:"color"`
+}
+
+// Table is a table block. Its rows are table_row children, each with
+// TableWidth cells.
+type Table struct {
+	TableWidth      int  `json:"table_width"`
+	HasColumnHeader bool `json:"has_column_header"`
+	HasRowHeader    bool `json:"has_row_header"`
+}
+
+type TableRow struct {
+	Cells [][]RichText `json:"cells"`
+}
+
+// ColumnList is a column_list block. Its columns are column children.
+type ColumnList struct{}
+
+type Column struct {
+	WidthRatio *float64 `json:"width_ratio,omitempty"`
+}
+
+// SyncedBlock is either an original synced block, with a nil SyncedFrom and
+// the content as children, or a duplicate pointing at the original.
+type SyncedBlock struct {
+	SyncedFrom *SyncedFrom `json:"synced_from"`
+}
+
+type SyncedFrom struct {
+	Type    string `json:"type"`
+	BlockID string `json:"block_id"`
+}
+
+type ChildPage struct {
+	Title string `json:"title"`
+}
+
+type ChildDatabase struct {
+	Title string `json:"title"`
+}
+
+// LinkToPage is a link_to_page block. Type says which ID is set.
+type LinkToPage struct {
+	Type       string `json:"type"`
+	PageID     string `json:"page_id,omitempty"`
+	DatabaseID string `json:"database_id,omitempty"`
+}
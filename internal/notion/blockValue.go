@@ -0,0 +1,198 @@
+package notion
+
+import "encoding/json"
+
+// Block is a block to be written to Notion, built with the block
+// constructors. Value is the type-specific content, such as a *Paragraph.
+type Block struct {
+	Type     string
+	Value    any
+	Children []Block
+}
+
+func (b Block) MarshalJSON() ([]byte, error) {
+	content, err := b.content()
+	if err != nil {
+		return nil, err
+	}
+	if len(b.Children) > 0 {
+		content["children"] = b.Children
+	}
+	return json.Marshal(map[string]any{
+		"object": "block",
+		"type":   b.Type,
+		b.Type:   content,
+	})
+}
+
+// content returns the block's Value as a JSON object, without children.
+func (b Block) content() (map[string]any, error) {
+	content := map[string]any{}
+	if b.Value != nil {
+		raw, err := json.Marshal(b.Value)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &content); err != nil {
+			return nil, err
+		}
+	}
+	delete(content, "children")
+	return content, nil
+}
+
+// blockContent marshals only the type-specific content of a block, which is
+// what block updates send.
+type blockContent Block
+
+func (b blockContent) MarshalJSON() ([]byte, error) {
+	content, err := Block(b).content()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(content)
+}
+
+// WithChildren nests blocks under b. Only some block types, such as list
+// items, toggles, callouts and columns, accept children.
+func (b Block) WithChildren(children ...Block) Block {
+	b.Children = append(b.Children, children...)
+	return b
+}
+
+// ParagraphBlock returns a paragraph of the given rich text. Use NewRichText
+// for plain strings.
+func ParagraphBlock(richText ...RichText) Block {
+	return Block{Type: "paragraph", Value: &Paragraph{RichText: emptyIfNil(richText), Color: "default"}}
+}
+
+func Heading1Block(richText ...RichText) Block {
+	return Block{Type: "heading_1", Value: &Heading{RichText: emptyIfNil(richText), Color: "default"}}
+}
+
+func Heading2Block(richText ...RichText) Block {
+	return Block{Type: "heading_2", Value: &Heading{RichText: emptyIfNil(richText), Color: "default"}}
+}
+
+func Heading3Block(richText ...RichText) Block {
+	return Block{Type: "heading_3", Value: &Heading{RichText: emptyIfNil(richText), Color: "default"}}
+}
+
+func BulletedListItemBlock(richText ...RichText) Block {
+	return Block{Type: "bulleted_list_item", Value: &Paragraph{RichText: emptyIfNil(richText), Color: "default"}}
+}
+
+func NumberedListItemBlock(richText ...RichText) Block {
+	return Block{Type: "numbered_list_item", Value: &Paragraph{RichText: emptyIfNil(richText), Color: "default"}}
+}
+
+func ToDoBlock(checked bool, richText ...RichText) Block {
+	return Block{Type: "to_do", Value: &ToDo{RichText: emptyIfNil(richText), Checked: checked, Color: "default"}}
+}
+
+// ToggleBlock returns a toggle; its hidden content goes in WithChildren.
+func ToggleBlock(richText ...RichText) Block {
+	return Block{Type: "toggle", Value: &Paragraph{RichText: emptyIfNil(richText), Color: "default"}}
+}
+
+func QuoteBlock(richText ...RichText) Block {
+	return Block{Type: "quote", Value: &Paragraph{RichText: emptyIfNil(richText), Color: "default"}}
+}
+
+// calloutValue is the request body content of a callout, whose icon is sent
+// in the request shape.
+type calloutValue struct {
+	RichText []RichText `json:"rich_text"`
+	Icon     fileValue  `json:"icon"`
+	Color    string     `json:"color"`
+}
+
+// CalloutBlock returns a callout with an icon, such as EmojiIcon("💡").
+func CalloutBlock(icon FileObject, richText ...RichText) Block {
+	return Block{Type: "callout", Value: &calloutValue{RichText: emptyIfNil(richText), Icon: fileValue{FileObject: icon}, Color: "default"}}
+}
+
+// CodeBlock returns a code block. language must be one Notion knows, such as
+// "go" or "plain text".
+func CodeBlock(language string, code string) Block {
+	if language == "" {
+		language = "plain text"
+	}
+	return Block{Type: "code", Value: &Code{RichText: emptyIfNil(NewRichText(code)), Caption: []RichText{}, Language: language}}
+}
+
+func EquationBlock(expression string) Block {
+	return Block{Type: "equation", Value: &Equation{Expression: expression}}
+}
+
+func DividerBlock() Block {
+	return Block{Type: "divider", Value: &Divider{}}
+}
+
+func BreadcrumbBlock() Block {
+	return Block{Type: "breadcrumb", Value: &Breadcrumb{}}
+}
+
+func TableOfContentsBlock() Block {
+	return Block{Type: "table_of_contents", Value: &TableOfContents{Color: "default"}}
+}
+
+func BookmarkBlock(url string) Block {
+	return Block{Type: "bookmark", Value: &Bookmark{URL: url, Caption: []RichText{}}}
+}
+
+func EmbedBlock(url string) Block {
+	return Block{Type: "embed", Value: &Embed{URL: url}}
+}
+
+func ImageBlock(file FileObject, caption ...RichText) Block {
+	return Block{Type: "image", Value: fileBlockValue{file: file, caption: caption}}
+}
+
+func VideoBlock(file FileObject, caption ...RichText) Block {
+	return Block{Type: "video", Value: fileBlockValue{file: file, caption: caption}}
+}
+
+func AudioBlock(file FileObject, caption ...RichText) Block {
+	return Block{Type: "audio", Value: fileBlockValue{file: file, caption: caption}}
+}
+
+func PDFBlock(file FileObject, caption ...RichText) Block {
+	return Block{Type: "pdf", Value: fileBlockValue{file: file, caption: caption}}
+}
+
+// FileAttachmentBlock returns a downloadable file block.
+func FileAttachmentBlock(file FileObject, caption ...RichText) Block {
+	return Block{Type: "file", Value: fileBlockValue{file: file, caption: caption}}
+}
+
+// TableBlock returns a table of TableRowBlock rows. Every row must have the
+// same number of cells.
+func TableBlock(hasColumnHeader bool, hasRowHeader bool, rows ...Block) Block {
+	width := 0
+	if len(rows) > 0 {
+		if row, ok := rows[0].Value.(*TableRow); ok {
+			width = len(row.Cells)
+		}
+	}
+	table := Block{Type: "table", Value: &Table{TableWidth: width, HasColumnHeader: hasColumnHeader, HasRowHeader: hasRowHeader}}
+	return table.WithChildren(rows...)
+}
+
+func TableRowBlock(cells ...[]RichText) Block {
+	row := &TableRow{Cells: [][]RichText{}}
+	for _, cell := range cells {
+		row.Cells = append(row.Cells, emptyIfNil(cell))
+	}
+	return Block{Type: "table_row", Value: row}
+}
+
+// ColumnListBlock lays out ColumnBlock columns side by side. Notion needs at
+// least two.
+func ColumnListBlock(columns ...Block) Block {
+	return Block{Type: "column_list", Value: &ColumnList{}}.WithChildren(columns...)
+}
+
+func ColumnBlock(children ...Block) Block {
+	return Block{Type: "column", Value: &Column{}}.WithChildren(children...)
+}
@@ -0,0 +1,142 @@
+package notion_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/danecwalker/portfolio/internal/notion"
+	"github.com/danecwalker/portfolio/internal/notion/notiontest"
+)
+
+// newFake starts a notiontest server and a quiet client for it.
+func newFake(t *testing.T, opts ...notion.Option) (*notiontest.Server, *notion.Client) {
+	t.Helper()
+	srv := notiontest.NewServer()
+	t.Cleanup(srv.Close)
+	return srv, srv.Client(append([]notion.Option{notion.WithLogger(slog.New(slog.DiscardHandler))}, opts...)...)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// appendBatch is what one append request sent.
+type appendBatch struct {
+	size  int
+	after string
+}
+
+func TestAppendBatches(t *testing.T) {
+	tests := []struct {
+		name        string
+		count       int
+		after       string
+		wantBatches []int
+		wantOrder   func(appended []string) []string
+	}{
+		{
+			name:        "one batch",
+			count:       3,
+			wantBatches: []int{3},
+			wantOrder:   func(appended []string) []string { return append([]string{"first", "last"}, appended...) },
+		},
+		{
+			name:        "batches of 100",
+			count:       250,
+			wantBatches: []int{100, 100, 50},
+			wantOrder:   func(appended []string) []string { return append([]string{"first", "last"}, appended...) },
+		},
+		{
+			name:        "after a sibling",
+			count:       150,
+			after:       "first",
+			wantBatches: []int{100, 50},
+			wantOrder: func(appended []string) []string {
+				return append(append([]string{"first"}, appended...), "last")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var batches []appendBatch
+			record := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if req.Method == http.MethodPatch {
+					data, _ := io.ReadAll(req.Body)
+					req.Body = io.NopCloser(bytes.NewReader(data))
+					var body struct {
+						Children []json.RawMessage `json:"children"`
+						After    string            `json:"after"`
+					}
+					json.Unmarshal(data, &body)
+					mu.Lock()
+					batches = append(batches, appendBatch{size: len(body.Children), after: body.After})
+					mu.Unlock()
+				}
+				return http.DefaultTransport.RoundTrip(req)
+			})
+			srv, client := newFake(t, notion.WithTransport(record))
+			srv.AddPage(notion.PageResponse{ID: "notes"})
+			srv.AddBlocks("notes",
+				notion.BlockGetResponse{ID: "first", Type: "paragraph", Paragraph: &notion.Paragraph{RichText: notion.NewRichText("first")}},
+				notion.BlockGetResponse{ID: "last", Type: "paragraph", Paragraph: &notion.Paragraph{RichText: notion.NewRichText("last")}},
+			)
+
+			var blocks []notion.Block
+			var appended []string
+			for i := range tt.count {
+				blocks = append(blocks, notion.ParagraphBlock(notion.NewRichText(strconv.Itoa(i))...))
+				appended = append(appended, strconv.Itoa(i))
+			}
+			req := notion.Blocks("notes").Append(blocks...)
+			if tt.after != "" {
+				req.After(tt.after)
+			}
+			added, err := req.Fetch(client)
+			if err != nil {
+				t.Fatalf("append: %v", err)
+			}
+			if len(added.Results) != tt.count {
+				t.Fatalf("append returned %d blocks, want %d", len(added.Results), tt.count)
+			}
+
+			// every batch after the first goes after the last block the
+			// previous one created
+			sent := 0
+			for i, batch := range batches {
+				want := appendBatch{size: tt.wantBatches[min(i, len(tt.wantBatches)-1)], after: tt.after}
+				if i > 0 {
+					want.after = added.Results[sent-1].ID
+				}
+				if batch != want {
+					t.Errorf("batch %d = %+v, want %+v", i, batch, want)
+				}
+				sent += batch.size
+			}
+			if len(batches) != len(tt.wantBatches) {
+				t.Errorf("sent %d batches, want %d", len(batches), len(tt.wantBatches))
+			}
+
+			children, err := notion.Blocks("notes").Query().All().Fetch(client)
+			if err != nil {
+				t.Fatalf("children: %v", err)
+			}
+			var got []string
+			for _, block := range children.Results {
+				got = append(got, notion.PlainText(block.Paragraph.RichText))
+			}
+			if want := tt.wantOrder(appended); !slices.Equal(got, want) {
+				t.Errorf("children = %v, want %v", got, want)
+			}
+		})
+	}
+}
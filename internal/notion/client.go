@@ -1,11 +1,20 @@
 package notion
 
-import "net/http"
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+)
 
 type Client struct {
 	baseURL    string
 	authToken  string
 	httpClient http.Client
+	retry      RetryPolicy
+	limiter    *limiter
+	version    string
+	logger     *slog.Logger
 }
 
 type HeaderRoundTripper struct {
@@ -13,31 +22,165 @@ type HeaderRoundTripper struct {
 	headers map[string]string
 }
 
+// RoundTrip adds the default headers, keeping any the request already set,
+// such as the multipart Content-Type of file uploads.
 func (h *HeaderRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	for k, v := range h.headers {
-		req.Header.Set(k, v)
+		if req.Header.Get(k) == "" {
+			req.Header.Set(k, v)
+		}
 	}
 	return h.rt.RoundTrip(req)
 }
 
-func NewClient(authToken string) *Client {
-	return &Client{
-		baseURL:   "https://api.notion.com/v1/",
+const (
+	DefaultBaseURL       = "https://api.notion.com/v1/"
+	DefaultNotionVersion = "2025-09-03"
+)
+
+func NewClient(authToken string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:   DefaultBaseURL,
 		authToken: authToken,
+		version:   DefaultNotionVersion,
 		httpClient: http.Client{
 			Timeout: http.DefaultClient.Timeout,
-			Transport: &HeaderRoundTripper{
-				rt: http.DefaultTransport,
-				headers: map[string]string{
-					"Authorization":  "Bearer " + authToken,
-					"Notion-Version": "2025-09-03",
-					"Content-Type":   "application/json",
-				},
-			},
 		},
+		retry:   DefaultRetryPolicy,
+		limiter: newLimiter(DefaultRateLimit, DefaultRateBurst),
+		logger:  slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	rt := c.httpClient.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
 	}
+	c.httpClient.Transport = &HeaderRoundTripper{
+		rt: rt,
+		headers: map[string]string{
+			"Authorization":  "Bearer " + authToken,
+			"Notion-Version": c.version,
+			"Content-Type":   "application/json",
+		},
+	}
+	return c
+}
+
+// LimiterStats returns the wait metrics of the client's rate limiter. It is
+// zero when limiting is disabled.
+func (c *Client) LimiterStats() LimiterStats {
+	if c.limiter == nil {
+		return LimiterStats{}
+	}
+	return c.limiter.Stats()
 }
 
 func (c *Client) buildURL(endpoint string) string {
 	return c.baseURL + endpoint
 }
+
+// do sends req and decodes a successful response body into v. Non-2xx
+// responses are returned as *APIError. Rate-limited and gateway failures are
+// retried according to the client's RetryPolicy.
+func (c *Client) do(req *http.Request, v any) error {
+	start := time.Now()
+	var res result
+	var err error
+	var waited time.Duration
+	attempt := 0
+	for ; ; attempt++ {
+		res, err = c.send(req, v)
+		waited += res.waited
+		if err == nil {
+			break
+		}
+
+		apiErr, ok := asAPIError(err)
+		if !ok || attempt >= c.retry.MaxRetries || !retryableStatus(apiErr.Status) || !retryable(req) {
+			break
+		}
+		delay, ok := c.retry.backoff(attempt, apiErr.RetryAfter)
+		if !ok {
+			break
+		}
+		if req.GetBody != nil {
+			req.Body, err = req.GetBody()
+			if err != nil {
+				break
+			}
+		}
+
+		c.logger.LogAttrs(req.Context(), slog.LevelDebug, "retrying notion request",
+			slog.String("method", req.Method),
+			slog.String("endpoint", req.URL.Path),
+			slog.Int("status", res.status),
+			slog.String("request_id", res.requestID),
+			slog.Int("attempt", attempt+1),
+			slog.Duration("backoff", delay),
+		)
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			err = req.Context().Err()
+		case <-timer.C:
+			continue
+		}
+		break
+	}
+
+	// only the path is logged: query strings carry cursors, and headers carry
+	// the integration token
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("endpoint", req.URL.Path),
+		slog.Int("status", res.status),
+		slog.String("request_id", res.requestID),
+		slog.Duration("latency", time.Since(start)),
+		slog.Int("retries", attempt),
+		slog.Duration("rate_limit_wait", waited),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		c.logger.LogAttrs(req.Context(), slog.LevelWarn, "notion request failed", attrs...)
+		return err
+	}
+	c.logger.LogAttrs(req.Context(), slog.LevelInfo, "notion request", attrs...)
+	return nil
+}
+
+// result describes a single attempt for logging.
+type result struct {
+	status    int
+	requestID string
+	waited    time.Duration
+}
+
+func (c *Client) send(req *http.Request, v any) (result, error) {
+	var res result
+	if c.limiter != nil {
+		waited, err := c.limiter.Wait(req.Context())
+		res.waited = waited
+		if err != nil {
+			return res, err
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return res, err
+	}
+	defer resp.Body.Close()
+	res.status = resp.StatusCode
+	res.requestID = resp.Header.Get("X-Request-Id")
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := newAPIError(resp)
+		res.requestID = apiErr.RequestID
+		return res, apiErr
+	}
+	return res, json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,184 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Comment is a comment on a page or block. Comments with the same
+// DiscussionID form one thread.
+type Comment struct {
+	Object         string              `json:"object"`
+	ID             string              `json:"id"`
+	Parent         PageParent          `json:"parent"`
+	DiscussionID   string              `json:"discussion_id"`
+	CreatedTime    time.Time           `json:"created_time"`
+	LastEditedTime time.Time           `json:"last_edited_time"`
+	CreatedBy      User                `json:"created_by"`
+	RichText       []RichText          `json:"rich_text"`
+	DisplayName    *CommentDisplayName `json:"display_name,omitempty"`
+	Attachments    []CommentAttachment `json:"attachments,omitempty"`
+}
+
+// CommentDisplayName is the author name shown on the comment. Type is
+// "integration", "user" or "custom".
+type CommentDisplayName struct {
+	Type         string `json:"type"`
+	ResolvedName string `json:"resolved_name"`
+}
+
+type CommentAttachment struct {
+	Category string `json:"category"`
+	File     File   `json:"file"`
+}
+
+type CommentRequest struct {
+	method  string
+	blockId string
+	body    map[string]any
+	cursor
+}
+
+type CommentResponse struct {
+	Object string `json:"object"`
+	Comment
+	CommentListResponse
+}
+
+type CommentListResponse struct {
+	Results []Comment `json:"results"`
+	Pagination
+}
+
+// Comments lists the unresolved comments on a page or block, oldest first.
+func Comments(blockId string) *CommentRequest {
+	return &CommentRequest{
+		method:  "LIST",
+		blockId: blockId,
+	}
+}
+
+// CreateComment starts a new discussion on a page.
+func CreateComment(pageId string, richText ...RichText) *CommentRequest {
+	return &CommentRequest{
+		method: "CREATE",
+		body: map[string]any{
+			"parent":    ParentPage(pageId),
+			"rich_text": emptyIfNil(richText),
+		},
+	}
+}
+
+// ReplyToDiscussion adds a comment to an existing thread.
+func ReplyToDiscussion(discussionId string, richText ...RichText) *CommentRequest {
+	return &CommentRequest{
+		method: "CREATE",
+		body: map[string]any{
+			"discussion_id": discussionId,
+			"rich_text":     emptyIfNil(richText),
+		},
+	}
+}
+
+// DisplayName shows a new comment as written by name instead of by the
+// integration.
+func (cr *CommentRequest) DisplayName(name string) *CommentRequest {
+	if cr.body != nil {
+		cr.body["display_name"] = map[string]any{
+			"type":   "custom",
+			"custom": map[string]any{"name": name},
+		}
+	}
+	return cr
+}
+
+func (cr *CommentRequest) StartCursor(startCursor string) *CommentRequest {
+	cr.startCursor = startCursor
+	return cr
+}
+
+// PageSize sets the number of comments per page (Notion allows at most 100).
+func (cr *CommentRequest) PageSize(pageSize int) *CommentRequest {
+	cr.pageSize = pageSize
+	return cr
+}
+
+// All makes Fetch follow next_cursor until every comment has been listed.
+func (cr *CommentRequest) All() *CommentRequest {
+	cr.all = true
+	return cr
+}
+
+func (cr *CommentRequest) Fetch(c *Client) (*CommentResponse, error) {
+	return cr.FetchContext(context.Background(), c)
+}
+
+func (cr *CommentRequest) FetchContext(ctx context.Context, c *Client) (*CommentResponse, error) {
+	commentResp, err := cr.fetchPage(ctx, c, cr.startCursor)
+	if err != nil {
+		return nil, err
+	}
+	if cr.method != "LIST" || !cr.all {
+		return commentResp, nil
+	}
+
+	for {
+		next, ok := commentResp.Pagination.next()
+		if !ok {
+			return commentResp, nil
+		}
+		page, err := cr.fetchPage(ctx, c, next)
+		if err != nil {
+			return nil, err
+		}
+		commentResp.Results = append(commentResp.Results, page.Results...)
+		commentResp.Pagination = page.Pagination
+	}
+}
+
+func (cr *CommentRequest) fetchPage(ctx context.Context, c *Client, startCursor string) (*CommentResponse, error) {
+	var reqBody io.Reader
+	var method string
+	query := url.Values{}
+
+	switch cr.method {
+	case "CREATE":
+		method = http.MethodPost
+		jsonBytes, err := json.Marshal(cr.body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(jsonBytes)
+	default:
+		method = http.MethodGet
+		query.Set("block_id", cr.blockId)
+		if startCursor != "" {
+			query.Set("start_cursor", startCursor)
+		}
+		if cr.pageSize > 0 {
+			query.Set("page_size", strconv.Itoa(cr.pageSize))
+		}
+	}
+
+	reqURL := c.buildURL("comments")
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var commentResp CommentResponse
+	err = c.do(req, &commentResp)
+	if err != nil {
+		return nil, err
+	}
+	return &commentResp, nil
+}
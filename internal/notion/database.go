@@ -0,0 +1,84 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DatabaseRequest retrieves a database. Since Notion-Version 2025-09-03 a
+// database is a container for one or more data sources, which hold the rows
+// and schema.
+type DatabaseRequest struct {
+	method     string
+	databaseId string
+}
+
+type DatabaseResponse struct {
+	Object         string          `json:"object"`
+	ID             string          `json:"id"`
+	Title          []RichText      `json:"title"`
+	Description    []RichText      `json:"description"`
+	Parent         PageParent      `json:"parent"`
+	IsInline       bool            `json:"is_inline"`
+	InTrash        bool            `json:"in_trash"`
+	IsLocked       bool            `json:"is_locked"`
+	CreatedTime    time.Time       `json:"created_time"`
+	LastEditedTime time.Time       `json:"last_edited_time"`
+	Datasources    []DatasourceRef `json:"data_sources"`
+	Icon           FileObject      `json:"icon"`
+	Cover          FileObject      `json:"cover"`
+	URL            string          `json:"url"`
+	PublicURL      string          `json:"public_url"`
+}
+
+type DatasourceRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func Database(databaseId string) *DatabaseRequest {
+	return &DatabaseRequest{
+		method:     "GET",
+		databaseId: databaseId,
+	}
+}
+
+func (dr *DatabaseRequest) Fetch(c *Client) (*DatabaseResponse, error) {
+	return dr.FetchContext(context.Background(), c)
+}
+
+func (dr *DatabaseRequest) FetchContext(ctx context.Context, c *Client) (*DatabaseResponse, error) {
+	url := c.buildURL("databases/" + dr.databaseId)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var dbResp DatabaseResponse
+	err = c.do(req, &dbResp)
+	if err != nil {
+		return nil, err
+	}
+	return &dbResp, nil
+}
+
+// Datasource picks the ID of the data source named name, ignoring case. An
+// empty name picks the only data source and fails if there are several.
+func (d *DatabaseResponse) Datasource(name string) (string, error) {
+	if name == "" {
+		if len(d.Datasources) != 1 {
+			return "", fmt.Errorf("notion: database %s has %d data sources; choose one by name", d.ID, len(d.Datasources))
+		}
+		return d.Datasources[0].ID, nil
+	}
+
+	for _, ds := range d.Datasources {
+		if strings.EqualFold(ds.Name, name) {
+			return ds.ID, nil
+		}
+	}
+	return "", fmt.Errorf("notion: database %s has no data source named %q", d.ID, name)
+}
@@ -2,8 +2,8 @@ package notion
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 	"time"
@@ -12,7 +12,8 @@ import (
 type DatasourceRequest struct {
 	method       string
 	datasourceId string
-	body         []byte
+	payload      map[string]interface{}
+	cursor
 }
 
 type DatasourceResponse struct {
@@ -22,54 +23,99 @@ type DatasourceResponse struct {
 }
 
 type DatasourceGetResponse struct {
-	ID               string                 `json:"id"`
-	CreatedTime      time.Time              `json:"created_time"`
-	LastEditedTime   time.Time              `json:"last_edited_time"`
-	Properties       map[string]interface{} `json:"properties"`
-	Parent           DatasourceParent       `json:"parent"`
-	DatasourceParent PageParent             `json:"database_parent"`
-	Archived         bool                   `json:"archived"`
-	Inline           bool                   `json:"is_inline"`
-	Icon             FileObject             `json:"icon"`
-	Cover            FileObject             `json:"cover"`
-	URL              string                 `json:"url"`
-	InTrash          bool                   `json:"in_trash"`
+	ID             string                    `json:"id"`
+	Title          []RichText                `json:"title"`
+	Description    []RichText                `json:"description"`
+	CreatedTime    time.Time                 `json:"created_time"`
+	LastEditedTime time.Time                 `json:"last_edited_time"`
+	Properties     map[string]PropertySchema `json:"properties"`
+	Parent         DatasourceParent          `json:"parent"`
+	DatabaseParent PageParent                `json:"database_parent"`
+	Archived       bool                      `json:"archived"`
+	Inline         bool                      `json:"is_inline"`
+	Icon           FileObject                `json:"icon"`
+	Cover          FileObject                `json:"cover"`
+	URL            string                    `json:"url"`
+	InTrash        bool                      `json:"in_trash"`
 }
 
 type DatasourceQueryResponse struct {
 	Results []PageResponse `json:"results"`
+	Pagination
 }
 
 func Datasource(datasourceId string) *DatasourceRequest {
 	return &DatasourceRequest{
 		method:       "GET",
 		datasourceId: datasourceId,
-		body:         nil,
+		payload:      nil,
 	}
 }
 
-func (pr *DatasourceRequest) Query(filter map[string]interface{}, sort []map[string]interface{}) *DatasourceRequest {
+func (pr *DatasourceRequest) Query(filter Filter, sorts ...Sort) *DatasourceRequest {
 	pr.method = "QUERY"
 
 	payload := map[string]interface{}{}
 	if filter != nil {
 		payload["filter"] = filter
 	}
-	if sort != nil {
-		payload["sorts"] = sort
+	if len(sorts) > 0 {
+		payload["sorts"] = sorts
 	}
 
-	if filter == nil && sort == nil {
-		return pr
-	}
+	pr.payload = payload
+	return pr
+}
 
-	jsonBytes, _ := json.Marshal(payload)
+// StartCursor resumes a query from the next_cursor of a previous page.
+func (pr *DatasourceRequest) StartCursor(startCursor string) *DatasourceRequest {
+	pr.startCursor = startCursor
+	return pr
+}
 
-	pr.body = jsonBytes
+// PageSize sets the number of results per page (Notion allows at most 100).
+func (pr *DatasourceRequest) PageSize(pageSize int) *DatasourceRequest {
+	pr.pageSize = pageSize
+	return pr
+}
+
+// All makes Fetch follow next_cursor until every page of the query has been
+// read, returning the combined results.
+func (pr *DatasourceRequest) All() *DatasourceRequest {
+	pr.all = true
 	return pr
 }
 
 func (pr *DatasourceRequest) Fetch(c *Client) (*DatasourceResponse, error) {
+	return pr.FetchContext(context.Background(), c)
+}
+
+// FetchContext is Fetch with a context that cancels the Notion call, including
+// any rate limit wait or retry backoff.
+func (pr *DatasourceRequest) FetchContext(ctx context.Context, c *Client) (*DatasourceResponse, error) {
+	dsResp, err := pr.fetchPage(ctx, c, pr.startCursor)
+	if err != nil {
+		return nil, err
+	}
+	if pr.method != "QUERY" || !pr.all {
+		return dsResp, nil
+	}
+
+	for {
+		next, ok := dsResp.Pagination.next()
+		if !ok {
+			return dsResp, nil
+		}
+		page, err := pr.fetchPage(ctx, c, next)
+		if err != nil {
+			return nil, err
+		}
+		dsResp.Results = append(dsResp.Results, page.Results...)
+		dsResp.Pagination = page.Pagination
+	}
+}
+
+func (pr *DatasourceRequest) fetchPage(ctx context.Context, c *Client, startCursor string) (*DatasourceResponse, error) {
 	var reqBody io.Reader
 	var method string
 	var suffix string
@@ -81,10 +127,24 @@ func (pr *DatasourceRequest) Fetch(c *Client) (*DatasourceResponse, error) {
 		suffix = ""
 	case "QUERY":
 		method = http.MethodPost
-		if pr.body == nil {
+		payload := map[string]interface{}{}
+		for k, v := range pr.payload {
+			payload[k] = v
+		}
+		if startCursor != "" {
+			payload["start_cursor"] = startCursor
+		}
+		if pr.pageSize > 0 {
+			payload["page_size"] = pr.pageSize
+		}
+		if len(payload) == 0 {
 			reqBody = nil
 		} else {
-			reqBody = bytes.NewReader(pr.body)
+			jsonBytes, err := json.Marshal(payload)
+			if err != nil {
+				return nil, err
+			}
+			reqBody = bytes.NewReader(jsonBytes)
 		}
 		suffix = "/query"
 	default:
@@ -94,19 +154,13 @@ func (pr *DatasourceRequest) Fetch(c *Client) (*DatasourceResponse, error) {
 	}
 
 	url := c.buildURL("data_sources/" + pr.datasourceId + suffix)
-	fmt.Println(url)
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
 	var pageResp DatasourceResponse
-	err = json.NewDecoder(resp.Body).Decode(&pageResp)
+	err = c.do(req, &pageResp)
 	if err != nil {
 		return nil, err
 	}
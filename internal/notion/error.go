@@ -0,0 +1,110 @@
+package notion
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Error codes documented at https://developers.notion.com/reference/status-codes
+const (
+	ErrCodeInvalidJSON         = "invalid_json"
+	ErrCodeInvalidRequestURL   = "invalid_request_url"
+	ErrCodeInvalidRequest      = "invalid_request"
+	ErrCodeValidation          = "validation_error"
+	ErrCodeMissingVersion      = "missing_version"
+	ErrCodeUnauthorized        = "unauthorized"
+	ErrCodeRestrictedResource  = "restricted_resource"
+	ErrCodeObjectNotFound      = "object_not_found"
+	ErrCodeConflict            = "conflict_error"
+	ErrCodeRateLimited         = "rate_limited"
+	ErrCodeInternalServer      = "internal_server_error"
+	ErrCodeBadGateway          = "bad_gateway"
+	ErrCodeServiceUnavailable  = "service_unavailable"
+	ErrCodeDatabaseUnavailable = "database_connection_unavailable"
+	ErrCodeGatewayTimeout      = "gateway_timeout"
+)
+
+// APIError is returned by every Fetch when Notion responds with a non-2xx
+// status.
+type APIError struct {
+	Status    int    `json:"status"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"`
+	// RetryAfter is the server's Retry-After hint, if any.
+	RetryAfter time.Duration `json:"-"`
+}
+
+func (e *APIError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("notion: %d %s: %s (request %s)", e.Status, e.Code, e.Message, e.RequestID)
+	}
+	return fmt.Sprintf("notion: %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+// newAPIError builds an APIError from an error response, falling back to the
+// HTTP status when the body isn't a Notion error object.
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{}
+	body, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(body, apiErr); err != nil || apiErr.Code == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	apiErr.Status = resp.StatusCode
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Request-Id")
+	}
+	apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	return apiErr
+}
+
+func asAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// IsNotFound reports whether err is a Notion 404, which is also what Notion
+// returns for objects the integration hasn't been shared with.
+func IsNotFound(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && (apiErr.Status == http.StatusNotFound || apiErr.Code == ErrCodeObjectNotFound)
+}
+
+// IsRateLimited reports whether err is a Notion 429.
+func IsRateLimited(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && (apiErr.Status == http.StatusTooManyRequests || apiErr.Code == ErrCodeRateLimited)
+}
+
+// IsUnauthorized reports whether err was caused by a bad token or by the
+// integration lacking access to the resource.
+func IsUnauthorized(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && (apiErr.Status == http.StatusUnauthorized || apiErr.Status == http.StatusForbidden)
+}
+
+// IsValidation reports whether Notion rejected the request itself, e.g. a
+// malformed ID or an unknown property in a filter.
+func IsValidation(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && apiErr.Status == http.StatusBadRequest
+}
+
+// IsConflict reports whether err is a Notion 409 from a concurrent write.
+func IsConflict(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && (apiErr.Status == http.StatusConflict || apiErr.Code == ErrCodeConflict)
+}
+
+// IsServerError reports whether err is a 5xx from Notion.
+func IsServerError(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && apiErr.Status >= http.StatusInternalServerError
+}
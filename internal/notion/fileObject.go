@@ -1,11 +1,17 @@
 package notion
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
+// FileObject is a file, external link or emoji used for icons, covers, file
+// properties and media blocks. Name is only used in files properties.
 type FileObject struct {
 	Type       string     `json:"type"`
+	Name       string     `json:"name,omitempty"`
 	File       File       `json:"file"`
-	FileUpload FileUpload `json:"file_upload "`
+	FileUpload FileUpload `json:"file_upload"`
 	External   External   `json:"external"`
 	Emoji      string     `json:"emoji"`
 }
@@ -33,3 +39,57 @@ func (fo *FileObject) GetURL() string {
 		return ""
 	}
 }
+
+// ExternalFile links to a file hosted outside Notion.
+func ExternalFile(name string, url string) FileObject {
+	return FileObject{Type: "external", Name: name, External: External{URL: url}}
+}
+
+// EmojiIcon is an emoji page or callout icon.
+func EmojiIcon(emoji string) FileObject {
+	return FileObject{Type: "emoji", Emoji: emoji}
+}
+
+// fileValue is a file in the shape Notion expects in request bodies: only
+// the field named by Type. Name is only sent when named is set, since Notion
+// rejects it outside files properties.
+type fileValue struct {
+	FileObject
+	named bool
+}
+
+func (fv fileValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fv.fields())
+}
+
+func (fv fileValue) fields() map[string]any {
+	fo := fv.FileObject
+	body := map[string]any{"type": fo.Type}
+	if fv.named && fo.Name != "" {
+		body["name"] = fo.Name
+	}
+	switch fo.Type {
+	case "file":
+		body["file"] = fo.File
+	case "file_upload":
+		body["file_upload"] = fo.FileUpload
+	case "external":
+		body["external"] = fo.External
+	case "emoji":
+		body["emoji"] = fo.Emoji
+	}
+	return body
+}
+
+// fileBlockValue is the request body content of image, video, audio, file
+// and pdf blocks.
+type fileBlockValue struct {
+	file    FileObject
+	caption []RichText
+}
+
+func (fb fileBlockValue) MarshalJSON() ([]byte, error) {
+	body := fileValue{FileObject: fb.file}.fields()
+	body["caption"] = emptyIfNil(fb.caption)
+	return json.Marshal(body)
+}
@@ -0,0 +1,231 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"time"
+)
+
+const (
+	// MaxSinglePartSize is the largest file Notion accepts in one part.
+	MaxSinglePartSize = 20 << 20
+	// DefaultPartSize is the part size used for multi-part uploads. Notion
+	// requires parts between 5 MB and 20 MB, except the last.
+	DefaultPartSize = 10 << 20
+)
+
+// FileUploadRequest uploads a file to Notion, or retrieves the status of an
+// upload. A finished upload is attached by passing UploadedFile to a files
+// property, a page cover or icon, or a media block within an hour.
+type FileUploadRequest struct {
+	method      string
+	uploadId    string
+	filename    string
+	contentType string
+	content     io.Reader
+	size        int64
+	partSize    int64
+}
+
+type FileUploadResponse struct {
+	Object         string         `json:"object"`
+	ID             string         `json:"id"`
+	CreatedTime    time.Time      `json:"created_time"`
+	LastEditedTime time.Time      `json:"last_edited_time"`
+	ExpiryTime     *time.Time     `json:"expiry_time"`
+	Status         string         `json:"status"`
+	Filename       string         `json:"filename"`
+	ContentType    string         `json:"content_type"`
+	ContentLength  int64          `json:"content_length"`
+	UploadURL      string         `json:"upload_url,omitempty"`
+	CompleteURL    string         `json:"complete_url,omitempty"`
+	NumberOfParts  *NumberOfParts `json:"number_of_parts,omitempty"`
+}
+
+type NumberOfParts struct {
+	Total     int `json:"total"`
+	SentCount int `json:"sent_count"`
+}
+
+// Upload sends content to Notion. Files up to MaxSinglePartSize are sent in
+// one request; larger ones need Size so they can be split into parts.
+func Upload(filename string, contentType string, content io.Reader) *FileUploadRequest {
+	return &FileUploadRequest{
+		method:      "UPLOAD",
+		filename:    filename,
+		contentType: contentType,
+		content:     content,
+		size:        -1,
+		partSize:    DefaultPartSize,
+	}
+}
+
+// FileUploadByID retrieves an upload, e.g. to check its status.
+func FileUploadByID(uploadId string) *FileUploadRequest {
+	return &FileUploadRequest{
+		method:   "GET",
+		uploadId: uploadId,
+	}
+}
+
+// Size is the length of the content in bytes.
+func (fr *FileUploadRequest) Size(size int64) *FileUploadRequest {
+	fr.size = size
+	return fr
+}
+
+// PartSize overrides DefaultPartSize for multi-part uploads.
+func (fr *FileUploadRequest) PartSize(partSize int64) *FileUploadRequest {
+	fr.partSize = partSize
+	return fr
+}
+
+// UploadedFile references a finished upload for attaching. name is shown in
+// files properties.
+func UploadedFile(name string, uploadId string) FileObject {
+	return FileObject{Type: "file_upload", Name: name, FileUpload: FileUpload{ID: uploadId}}
+}
+
+func (fr *FileUploadRequest) Fetch(c *Client) (*FileUploadResponse, error) {
+	return fr.FetchContext(context.Background(), c)
+}
+
+func (fr *FileUploadRequest) FetchContext(ctx context.Context, c *Client) (*FileUploadResponse, error) {
+	if fr.method == "GET" {
+		return fr.send(ctx, c, http.MethodGet, "file_uploads/"+fr.uploadId, "", nil)
+	}
+
+	if fr.size < 0 {
+		// small files of unknown size can still go in one part
+		head, err := io.ReadAll(io.LimitReader(fr.content, MaxSinglePartSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(head) > MaxSinglePartSize {
+			return nil, errors.New("notion: files over 20 MB need Size for a multi-part upload")
+		}
+		fr.content = bytes.NewReader(head)
+		fr.size = int64(len(head))
+	}
+
+	if fr.size <= MaxSinglePartSize {
+		return fr.uploadSinglePart(ctx, c)
+	}
+	return fr.uploadMultiPart(ctx, c)
+}
+
+func (fr *FileUploadRequest) uploadSinglePart(ctx context.Context, c *Client) (*FileUploadResponse, error) {
+	upload, err := fr.create(ctx, c, map[string]any{
+		"mode": "single_part",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fr.sendPart(ctx, c, upload.ID, 0, fr.content)
+}
+
+func (fr *FileUploadRequest) uploadMultiPart(ctx context.Context, c *Client) (*FileUploadResponse, error) {
+	if fr.partSize < 5<<20 || fr.partSize > MaxSinglePartSize {
+		return nil, fmt.Errorf("notion: part size %d is outside Notion's 5 MB to 20 MB range", fr.partSize)
+	}
+	parts := int((fr.size + fr.partSize - 1) / fr.partSize)
+	upload, err := fr.create(ctx, c, map[string]any{
+		"mode":            "multi_part",
+		"number_of_parts": parts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for part := 1; part <= parts; part++ {
+		// the last part holds the remainder
+		want := min(fr.partSize, fr.size-int64(part-1)*fr.partSize)
+		chunk := make([]byte, want)
+		n, err := io.ReadFull(fr.content, chunk)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if int64(n) != want {
+			return nil, fmt.Errorf("notion: part %d of %s has %d bytes, expected %d from Size", part, fr.filename, n, want)
+		}
+
+		_, err = fr.sendPart(ctx, c, upload.ID, part, bytes.NewReader(chunk))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fr.send(ctx, c, http.MethodPost, "file_uploads/"+upload.ID+"/complete", "application/json", nil)
+}
+
+func (fr *FileUploadRequest) create(ctx context.Context, c *Client, payload map[string]any) (*FileUploadResponse, error) {
+	payload["filename"] = fr.filename
+	if fr.contentType != "" {
+		payload["content_type"] = fr.contentType
+	}
+	jsonBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return fr.send(ctx, c, http.MethodPost, "file_uploads", "application/json", jsonBytes)
+}
+
+// sendPart posts content as multipart/form-data. Part 0 means a single-part
+// upload, which sends no part_number.
+func (fr *FileUploadRequest) sendPart(ctx context.Context, c *Client, uploadId string, part int, content io.Reader) (*FileUploadResponse, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, fr.filename))
+	if fr.contentType != "" {
+		header.Set("Content-Type", fr.contentType)
+	}
+	fileField, err := form.CreatePart(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(fileField, content); err != nil {
+		return nil, err
+	}
+	if part > 0 {
+		if err := form.WriteField("part_number", strconv.Itoa(part)); err != nil {
+			return nil, err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	return fr.send(ctx, c, http.MethodPost, "file_uploads/"+uploadId+"/send", form.FormDataContentType(), body.Bytes())
+}
+
+func (fr *FileUploadRequest) send(ctx context.Context, c *Client, method string, endpoint string, contentType string, body []byte) (*FileUploadResponse, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	url := c.buildURL(endpoint)
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	var uploadResp FileUploadResponse
+	err = c.do(req, &uploadResp)
+	if err != nil {
+		return nil, err
+	}
+	return &uploadResp, nil
+}
@@ -0,0 +1,195 @@
+package notion_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math/rand/v2"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/danecwalker/portfolio/internal/notion"
+)
+
+// recordParts returns a transport that records the size of the file in each
+// send request, by part number.
+func recordParts(mu *sync.Mutex, sizes map[int]int) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/send") {
+			data, _ := io.ReadAll(req.Body)
+			req.Body = io.NopCloser(bytes.NewReader(data))
+			_, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+			form, err := multipart.NewReader(bytes.NewReader(data), params["boundary"]).ReadForm(64 << 20)
+			if err == nil {
+				part, _ := strconv.Atoi(strings.Join(form.Value["part_number"], ""))
+				if files := form.File["file"]; len(files) == 1 {
+					mu.Lock()
+					sizes[part] = int(files[0].Size)
+					mu.Unlock()
+				}
+				form.RemoveAll()
+			}
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	}
+}
+
+// randomContent returns n bytes that are the same on every run.
+func randomContent(n int) []byte {
+	var seed [32]byte
+	binary.LittleEndian.PutUint64(seed[:], uint64(n))
+	content := make([]byte, n)
+	rand.NewChaCha8(seed).Read(content)
+	return content
+}
+
+func TestUpload(t *testing.T) {
+	const mb = 1 << 20
+
+	tests := []struct {
+		name      string
+		size      int
+		known     bool
+		partSize  int64
+		wantParts map[int]int
+	}{
+		{
+			name:      "small file of unknown size",
+			size:      3 * mb,
+			wantParts: map[int]int{0: 3 * mb},
+		},
+		{
+			name:      "single part at the limit",
+			size:      notion.MaxSinglePartSize,
+			known:     true,
+			wantParts: map[int]int{0: notion.MaxSinglePartSize},
+		},
+		{
+			name:      "default part size",
+			size:      25 * mb,
+			known:     true,
+			wantParts: map[int]int{1: 10 * mb, 2: 10 * mb, 3: 5 * mb},
+		},
+		{
+			name:      "custom part size",
+			size:      21 * mb,
+			known:     true,
+			partSize:  7 * mb,
+			wantParts: map[int]int{1: 7 * mb, 2: 7 * mb, 3: 7 * mb},
+		},
+		{
+			name:      "short last part",
+			size:      20*mb + 1,
+			known:     true,
+			wantParts: map[int]int{1: 10 * mb, 2: 10 * mb, 3: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			sizes := map[int]int{}
+			srv, client := newFake(t, notion.WithTransport(recordParts(&mu, sizes)))
+
+			content := randomContent(tt.size)
+			req := notion.Upload("data.bin", "application/octet-stream", bytes.NewReader(content))
+			if tt.known {
+				req.Size(int64(tt.size))
+			}
+			if tt.partSize != 0 {
+				req.PartSize(tt.partSize)
+			}
+			upload, err := req.Fetch(client)
+			if err != nil {
+				t.Fatalf("upload: %v", err)
+			}
+
+			if upload.Status != "uploaded" || upload.ContentLength != int64(tt.size) {
+				t.Errorf("upload is %s with %d bytes, want uploaded with %d", upload.Status, upload.ContentLength, tt.size)
+			}
+			if parts := len(tt.wantParts); tt.wantParts[0] == 0 {
+				if upload.NumberOfParts == nil || upload.NumberOfParts.Total != parts || upload.NumberOfParts.SentCount != parts {
+					t.Errorf("NumberOfParts = %+v, want %d sent", upload.NumberOfParts, parts)
+				}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for part, want := range tt.wantParts {
+				if sizes[part] != want {
+					t.Errorf("part %d has %d bytes, want %d", part, sizes[part], want)
+				}
+			}
+			if len(sizes) != len(tt.wantParts) {
+				t.Errorf("sent parts %v, want %v", sizes, tt.wantParts)
+			}
+
+			_, got, ok := srv.FileUpload(upload.ID)
+			if !ok || !bytes.Equal(got, content) {
+				t.Errorf("uploaded content differs from the original (%d bytes, want %d)", len(got), len(content))
+			}
+		})
+	}
+}
+
+func TestUploadErrors(t *testing.T) {
+	const mb = 1 << 20
+
+	tests := []struct {
+		name     string
+		req      func() *notion.FileUploadRequest
+		wantErr  string
+		wantSent int
+	}{
+		{
+			name: "unknown size over the single part limit",
+			req: func() *notion.FileUploadRequest {
+				return notion.Upload("big.bin", "", bytes.NewReader(randomContent(notion.MaxSinglePartSize+1)))
+			},
+			wantErr: "need Size",
+		},
+		{
+			name: "part size too small",
+			req: func() *notion.FileUploadRequest {
+				return notion.Upload("data.bin", "", bytes.NewReader(randomContent(25*mb))).Size(25 * mb).PartSize(4 * mb)
+			},
+			wantErr: "outside Notion's 5 MB to 20 MB range",
+		},
+		{
+			name: "part size too large",
+			req: func() *notion.FileUploadRequest {
+				return notion.Upload("data.bin", "", bytes.NewReader(randomContent(25*mb))).Size(25 * mb).PartSize(21 * mb)
+			},
+			wantErr: "outside Notion's 5 MB to 20 MB range",
+		},
+		{
+			name: "content shorter than Size",
+			req: func() *notion.FileUploadRequest {
+				return notion.Upload("data.bin", "", bytes.NewReader(randomContent(15*mb))).Size(25 * mb)
+			},
+			wantErr:  "part 2 of data.bin has 5242880 bytes, expected 10485760 from Size",
+			wantSent: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			sizes := map[int]int{}
+			_, client := newFake(t, notion.WithTransport(recordParts(&mu, sizes)))
+
+			_, err := tt.req().Fetch(client)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("upload error = %v, want %q", err, tt.wantErr)
+			}
+			// nothing is sent from the part that came up short onwards
+			mu.Lock()
+			defer mu.Unlock()
+			if len(sizes) != tt.wantSent {
+				t.Errorf("sent parts %v, want %d", sizes, tt.wantSent)
+			}
+		})
+	}
+}
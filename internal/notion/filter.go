@@ -0,0 +1,454 @@
+package notion
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// maxFilterDepth is how deeply Notion lets compound filters nest.
+const maxFilterDepth = 2
+
+// Filter is a data source query filter. Build one with Prop or Timestamp and
+// combine them with And and Or.
+type Filter interface {
+	json.Marshaler
+	depth() int
+}
+
+// TimestampKind names the page timestamps that can be filtered and sorted on
+// without a property.
+type TimestampKind string
+
+const (
+	CreatedTime    TimestampKind = "created_time"
+	LastEditedTime TimestampKind = "last_edited_time"
+)
+
+// target is the part of a filter that says what is being compared: a
+// property, or a timestamp, and the nested type keys leading to the
+// condition.
+type target struct {
+	property  string
+	timestamp TimestampKind
+	path      []string
+}
+
+func (t target) with(key string) target {
+	path := make([]string, len(t.path), len(t.path)+1)
+	copy(path, t.path)
+	t.path = append(path, key)
+	return t
+}
+
+func (t target) cond(op string, value any) Filter {
+	return condition{target: t, op: op, value: value}
+}
+
+type condition struct {
+	target
+	op    string
+	value any
+}
+
+func (f condition) depth() int {
+	return 0
+}
+
+func (f condition) MarshalJSON() ([]byte, error) {
+	body := map[string]any{f.op: f.value}
+	for i := len(f.path) - 1; i >= 0; i-- {
+		body = map[string]any{f.path[i]: body}
+	}
+	if f.timestamp != "" {
+		body["timestamp"] = f.timestamp
+	} else {
+		body["property"] = f.property
+	}
+	return json.Marshal(body)
+}
+
+type compound struct {
+	op      string
+	filters []Filter
+}
+
+func (f compound) depth() int {
+	d := 0
+	for _, child := range f.filters {
+		d = max(d, child.depth())
+	}
+	return d + 1
+}
+
+func (f compound) MarshalJSON() ([]byte, error) {
+	if f.depth() > maxFilterDepth {
+		return nil, fmt.Errorf("notion: compound filters nest at most %d levels deep", maxFilterDepth)
+	}
+	filters := f.filters
+	if filters == nil {
+		filters = []Filter{}
+	}
+	return json.Marshal(map[string]any{f.op: filters})
+}
+
+// And matches pages that pass every filter.
+func And(filters ...Filter) Filter {
+	return compound{op: "and", filters: filters}
+}
+
+// Or matches pages that pass any filter.
+func Or(filters ...Filter) Filter {
+	return compound{op: "or", filters: filters}
+}
+
+// PropertyFilter picks the type of the property being filtered. Its methods
+// must match the property's type in the data source schema.
+type PropertyFilter struct {
+	target
+}
+
+// Prop starts a filter on the property with the given name or ID.
+func Prop(name string) PropertyFilter {
+	return PropertyFilter{target{property: name}}
+}
+
+// Timestamp starts a filter on when pages were created or last edited.
+func Timestamp(kind TimestampKind) DateFilter {
+	return DateFilter{target{timestamp: kind}.with(string(kind))}
+}
+
+func (p PropertyFilter) Checkbox() CheckboxFilter {
+	return CheckboxFilter{p.with("checkbox")}
+}
+
+func (p PropertyFilter) Date() DateFilter {
+	return DateFilter{p.with("date")}
+}
+
+func (p PropertyFilter) Files() EmptyFilter {
+	return EmptyFilter{p.with("files")}
+}
+
+func (p PropertyFilter) Formula() FormulaFilter {
+	return FormulaFilter{p.with("formula")}
+}
+
+func (p PropertyFilter) MultiSelect() ContainsFilter {
+	return ContainsFilter{p.with("multi_select")}
+}
+
+func (p PropertyFilter) Number() NumberFilter {
+	return NumberFilter{p.with("number")}
+}
+
+func (p PropertyFilter) People() ContainsFilter {
+	return ContainsFilter{p.with("people")}
+}
+
+func (p PropertyFilter) PhoneNumber() TextFilter {
+	return TextFilter{p.with("phone_number")}
+}
+
+func (p PropertyFilter) Relation() ContainsFilter {
+	return ContainsFilter{p.with("relation")}
+}
+
+func (p PropertyFilter) RichText() TextFilter {
+	return TextFilter{p.with("rich_text")}
+}
+
+func (p PropertyFilter) Rollup() RollupFilter {
+	return RollupFilter{p.with("rollup")}
+}
+
+func (p PropertyFilter) Select() SelectFilter {
+	return SelectFilter{p.with("select")}
+}
+
+func (p PropertyFilter) Status() SelectFilter {
+	return SelectFilter{p.with("status")}
+}
+
+func (p PropertyFilter) Title() TextFilter {
+	return TextFilter{p.with("title")}
+}
+
+func (p PropertyFilter) URL() TextFilter {
+	return TextFilter{p.with("url")}
+}
+
+func (p PropertyFilter) Email() TextFilter {
+	return TextFilter{p.with("email")}
+}
+
+func (p PropertyFilter) UniqueID() NumberFilter {
+	return NumberFilter{p.with("unique_id")}
+}
+
+func (p PropertyFilter) Verification() VerificationFilter {
+	return VerificationFilter{p.with("verification")}
+}
+
+type CheckboxFilter struct {
+	target
+}
+
+func (f CheckboxFilter) Equals(value bool) Filter {
+	return f.cond("equals", value)
+}
+
+func (f CheckboxFilter) DoesNotEqual(value bool) Filter {
+	return f.cond("does_not_equal", value)
+}
+
+// EmptyFilter applies to properties that can only be tested for presence.
+type EmptyFilter struct {
+	target
+}
+
+func (f EmptyFilter) IsEmpty() Filter {
+	return f.cond("is_empty", true)
+}
+
+func (f EmptyFilter) IsNotEmpty() Filter {
+	return f.cond("is_not_empty", true)
+}
+
+// TextFilter applies to title, rich_text, url, email and phone_number
+// properties and to string formulas.
+type TextFilter struct {
+	target
+}
+
+func (f TextFilter) Equals(value string) Filter {
+	return f.cond("equals", value)
+}
+
+func (f TextFilter) DoesNotEqual(value string) Filter {
+	return f.cond("does_not_equal", value)
+}
+
+func (f TextFilter) Contains(value string) Filter {
+	return f.cond("contains", value)
+}
+
+func (f TextFilter) DoesNotContain(value string) Filter {
+	return f.cond("does_not_contain", value)
+}
+
+func (f TextFilter) StartsWith(value string) Filter {
+	return f.cond("starts_with", value)
+}
+
+func (f TextFilter) EndsWith(value string) Filter {
+	return f.cond("ends_with", value)
+}
+
+func (f TextFilter) IsEmpty() Filter {
+	return f.cond("is_empty", true)
+}
+
+func (f TextFilter) IsNotEmpty() Filter {
+	return f.cond("is_not_empty", true)
+}
+
+// NumberFilter applies to number and unique_id properties and to number
+// formulas.
+type NumberFilter struct {
+	target
+}
+
+func (f NumberFilter) Equals(value float64) Filter {
+	return f.cond("equals", value)
+}
+
+func (f NumberFilter) DoesNotEqual(value float64) Filter {
+	return f.cond("does_not_equal", value)
+}
+
+func (f NumberFilter) GreaterThan(value float64) Filter {
+	return f.cond("greater_than", value)
+}
+
+func (f NumberFilter) LessThan(value float64) Filter {
+	return f.cond("less_than", value)
+}
+
+func (f NumberFilter) GreaterThanOrEqualTo(value float64) Filter {
+	return f.cond("greater_than_or_equal_to", value)
+}
+
+func (f NumberFilter) LessThanOrEqualTo(value float64) Filter {
+	return f.cond("less_than_or_equal_to", value)
+}
+
+func (f NumberFilter) IsEmpty() Filter {
+	return f.cond("is_empty", true)
+}
+
+func (f NumberFilter) IsNotEmpty() Filter {
+	return f.cond("is_not_empty", true)
+}
+
+// SelectFilter applies to select and status properties, matched by option
+// name.
+type SelectFilter struct {
+	target
+}
+
+func (f SelectFilter) Equals(option string) Filter {
+	return f.cond("equals", option)
+}
+
+func (f SelectFilter) DoesNotEqual(option string) Filter {
+	return f.cond("does_not_equal", option)
+}
+
+func (f SelectFilter) IsEmpty() Filter {
+	return f.cond("is_empty", true)
+}
+
+func (f SelectFilter) IsNotEmpty() Filter {
+	return f.cond("is_not_empty", true)
+}
+
+// ContainsFilter applies to multi_select properties, matched by option name,
+// and to relation and people properties, matched by page or user ID.
+type ContainsFilter struct {
+	target
+}
+
+func (f ContainsFilter) Contains(value string) Filter {
+	return f.cond("contains", value)
+}
+
+func (f ContainsFilter) DoesNotContain(value string) Filter {
+	return f.cond("does_not_contain", value)
+}
+
+func (f ContainsFilter) IsEmpty() Filter {
+	return f.cond("is_empty", true)
+}
+
+func (f ContainsFilter) IsNotEmpty() Filter {
+	return f.cond("is_not_empty", true)
+}
+
+// DateFilter applies to date properties, date formulas and timestamps.
+// Relative ranges are evaluated by Notion in the workspace's time zone.
+type DateFilter struct {
+	target
+}
+
+func (f DateFilter) Equals(t time.Time) Filter {
+	return f.cond("equals", t.Format(time.RFC3339))
+}
+
+func (f DateFilter) Before(t time.Time) Filter {
+	return f.cond("before", t.Format(time.RFC3339))
+}
+
+func (f DateFilter) After(t time.Time) Filter {
+	return f.cond("after", t.Format(time.RFC3339))
+}
+
+func (f DateFilter) OnOrBefore(t time.Time) Filter {
+	return f.cond("on_or_before", t.Format(time.RFC3339))
+}
+
+func (f DateFilter) OnOrAfter(t time.Time) Filter {
+	return f.cond("on_or_after", t.Format(time.RFC3339))
+}
+
+func (f DateFilter) IsEmpty() Filter {
+	return f.cond("is_empty", true)
+}
+
+func (f DateFilter) IsNotEmpty() Filter {
+	return f.cond("is_not_empty", true)
+}
+
+func (f DateFilter) PastWeek() Filter {
+	return f.cond("past_week", struct{}{})
+}
+
+func (f DateFilter) PastMonth() Filter {
+	return f.cond("past_month", struct{}{})
+}
+
+func (f DateFilter) PastYear() Filter {
+	return f.cond("past_year", struct{}{})
+}
+
+func (f DateFilter) ThisWeek() Filter {
+	return f.cond("this_week", struct{}{})
+}
+
+func (f DateFilter) NextWeek() Filter {
+	return f.cond("next_week", struct{}{})
+}
+
+func (f DateFilter) NextMonth() Filter {
+	return f.cond("next_month", struct{}{})
+}
+
+func (f DateFilter) NextYear() Filter {
+	return f.cond("next_year", struct{}{})
+}
+
+// FormulaFilter picks the result type of the formula being filtered.
+type FormulaFilter struct {
+	target
+}
+
+func (f FormulaFilter) Checkbox() CheckboxFilter {
+	return CheckboxFilter{f.with("checkbox")}
+}
+
+func (f FormulaFilter) Date() DateFilter {
+	return DateFilter{f.with("date")}
+}
+
+func (f FormulaFilter) Number() NumberFilter {
+	return NumberFilter{f.with("number")}
+}
+
+func (f FormulaFilter) String() TextFilter {
+	return TextFilter{f.with("string")}
+}
+
+// RollupFilter filters on a rollup's aggregated value, or on the items of an
+// array rollup with Any, Every or None followed by the items' property type.
+type RollupFilter struct {
+	target
+}
+
+func (f RollupFilter) Any() PropertyFilter {
+	return PropertyFilter{f.with("any")}
+}
+
+func (f RollupFilter) Every() PropertyFilter {
+	return PropertyFilter{f.with("every")}
+}
+
+func (f RollupFilter) None() PropertyFilter {
+	return PropertyFilter{f.with("none")}
+}
+
+func (f RollupFilter) Date() DateFilter {
+	return DateFilter{f.with("date")}
+}
+
+func (f RollupFilter) Number() NumberFilter {
+	return NumberFilter{f.with("number")}
+}
+
+type VerificationFilter struct {
+	target
+}
+
+// Status matches "verified", "expired" or "none".
+func (f VerificationFilter) Status(status string) Filter {
+	return f.cond("status", status)
+}
@@ -0,0 +1,151 @@
+package notion_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/danecwalker/portfolio/internal/notion"
+)
+
+// assertJSON compares v's encoding with want, ignoring key order.
+func assertJSON(t *testing.T, v any, want string) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var got, expected any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if err := json.Unmarshal([]byte(want), &expected); err != nil {
+		t.Fatalf("decode want: %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %s\nwant %s", data, want)
+	}
+}
+
+func TestFilterJSON(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	hidden := notion.Prop("Hidden").Checkbox().Equals(false)
+
+	tests := []struct {
+		name   string
+		filter notion.Filter
+		want   string
+	}{
+		{
+			name:   "checkbox",
+			filter: hidden,
+			want:   `{"property":"Hidden","checkbox":{"equals":false}}`,
+		},
+		{
+			name:   "title contains",
+			filter: notion.Prop("Name").Title().Contains("go"),
+			want:   `{"property":"Name","title":{"contains":"go"}}`,
+		},
+		{
+			name:   "number",
+			filter: notion.Prop("Stars").Number().GreaterThanOrEqualTo(10),
+			want:   `{"property":"Stars","number":{"greater_than_or_equal_to":10}}`,
+		},
+		{
+			name:   "select is empty",
+			filter: notion.Prop("Kind").Select().IsEmpty(),
+			want:   `{"property":"Kind","select":{"is_empty":true}}`,
+		},
+		{
+			name:   "relative date",
+			filter: notion.Prop("Date").Date().PastWeek(),
+			want:   `{"property":"Date","date":{"past_week":{}}}`,
+		},
+		{
+			name:   "formula result",
+			filter: notion.Prop("Score").Formula().Number().LessThan(3),
+			want:   `{"property":"Score","formula":{"number":{"less_than":3}}}`,
+		},
+		{
+			name:   "timestamp",
+			filter: notion.Timestamp(notion.CreatedTime).After(at),
+			want:   `{"timestamp":"created_time","created_time":{"after":"2024-01-02T03:04:05Z"}}`,
+		},
+		{
+			name:   "empty and",
+			filter: notion.And(),
+			want:   `{"and":[]}`,
+		},
+		{
+			name: "nested compound",
+			filter: notion.And(
+				hidden,
+				notion.Or(
+					notion.Prop("Kind").Select().Equals("talk"),
+					notion.Prop("Tags").MultiSelect().Contains("go"),
+				),
+			),
+			want: `{"and":[
+				{"property":"Hidden","checkbox":{"equals":false}},
+				{"or":[
+					{"property":"Kind","select":{"equals":"talk"}},
+					{"property":"Tags","multi_select":{"contains":"go"}}
+				]}
+			]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertJSON(t, tt.filter, tt.want)
+		})
+	}
+}
+
+func TestFilterTooDeep(t *testing.T) {
+	filter := notion.And(notion.Or(notion.And(notion.Prop("Hidden").Checkbox().Equals(false))))
+	if _, err := json.Marshal(filter); err == nil {
+		t.Fatal("expected an error for three levels of compound filters")
+	}
+}
+
+func TestSortJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		sort    notion.Sort
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "property",
+			sort: notion.SortBy("Display Order"),
+			want: `{"property":"Display Order","direction":"ascending"}`,
+		},
+		{
+			name: "descending",
+			sort: notion.SortBy("Date").Desc(),
+			want: `{"property":"Date","direction":"descending"}`,
+		},
+		{
+			name: "timestamp",
+			sort: notion.SortByTimestamp(notion.LastEditedTime),
+			want: `{"timestamp":"last_edited_time","direction":"ascending"}`,
+		},
+		{
+			name:    "no target",
+			sort:    notion.Sort{}.Desc(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr {
+				if _, err := json.Marshal(tt.sort); err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			assertJSON(t, tt.sort, tt.want)
+		})
+	}
+}
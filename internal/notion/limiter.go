@@ -0,0 +1,136 @@
+package notion
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Notion allows an average of three requests per second per integration.
+const (
+	DefaultRateLimit = 3
+	DefaultRateBurst = 3
+)
+
+// WithRateLimit sets the sustained requests per second and burst size of the
+// client's limiter. A rate of zero or less disables limiting.
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(c *Client) {
+		if perSecond <= 0 {
+			c.limiter = nil
+			return
+		}
+		c.limiter = newLimiter(perSecond, burst)
+	}
+}
+
+// LimiterStats reports how much time requests have spent queued behind the
+// client's rate limiter.
+type LimiterStats struct {
+	Requests  int64
+	Waited    int64
+	TotalWait time.Duration
+	MaxWait   time.Duration
+}
+
+// AverageWait is the mean wait over all requests, including those that went
+// straight through.
+func (s LimiterStats) AverageWait() time.Duration {
+	if s.Requests == 0 {
+		return 0
+	}
+	return s.TotalWait / time.Duration(s.Requests)
+}
+
+// limiter is a token bucket. Every caller reserves the next token under the
+// lock, so waiters are served in the order they arrived.
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	stats  LimiterStats
+}
+
+func newLimiter(perSecond float64, burst int) *limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &limiter{
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token, going into debt if none are left, and returns how
+// long the caller has to wait for it.
+func (l *limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	return wait
+}
+
+// record adds a finished wait to the stats. Waits cancelled by the caller
+// are not recorded.
+func (l *limiter) record(wait time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stats.Requests++
+	if wait > 0 {
+		l.stats.Waited++
+		l.stats.TotalWait += wait
+		if wait > l.stats.MaxWait {
+			l.stats.MaxWait = wait
+		}
+	}
+}
+
+// cancel hands back a token reserved by a caller that gave up waiting.
+func (l *limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+}
+
+// Wait blocks until the caller may send a request.
+func (l *limiter) Wait(ctx context.Context) (time.Duration, error) {
+	wait := l.reserve()
+	if wait <= 0 {
+		l.record(0)
+		return 0, nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.cancel()
+		return 0, ctx.Err()
+	case <-timer.C:
+		l.record(wait)
+		return wait, nil
+	}
+}
+
+func (l *limiter) Stats() LimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
@@ -0,0 +1,65 @@
+package notion
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLimiterReserve(t *testing.T) {
+	l := newLimiter(10, 2)
+
+	// the burst goes straight through, then every request waits one more
+	// interval of 100ms than the one before it
+	want := []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond}
+	for i, want := range want {
+		got := l.reserve()
+		if got > want || got < want-20*time.Millisecond {
+			t.Errorf("reserve %d waits %v, want about %v", i, got, want)
+		}
+	}
+}
+
+func TestLimiterCancel(t *testing.T) {
+	l := newLimiter(1, 1)
+	l.reserve()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.Wait(ctx); err != context.Canceled {
+		t.Fatalf("Wait = %v, want context.Canceled", err)
+	}
+	if stats := l.Stats(); stats.Requests != 0 {
+		t.Errorf("cancelled wait was recorded: %+v", stats)
+	}
+
+	// the cancelled reservation gave its token back, so the next caller
+	// waits one interval rather than two
+	if got := l.reserve(); got > time.Second || got < 900*time.Millisecond {
+		t.Errorf("reserve after cancel waits %v, want about 1s", got)
+	}
+}
+
+func TestLimiterStats(t *testing.T) {
+	l := newLimiter(20, 1)
+	for range 3 {
+		if _, err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+
+	stats := l.Stats()
+	if stats.Requests != 3 || stats.Waited != 2 {
+		t.Errorf("stats = %+v, want 3 requests of which 2 waited", stats)
+	}
+	if stats.MaxWait <= 0 || stats.MaxWait > 50*time.Millisecond {
+		t.Errorf("MaxWait = %v, want at most 50ms", stats.MaxWait)
+	}
+	if got := stats.AverageWait(); got != stats.TotalWait/3 {
+		t.Errorf("AverageWait = %v, want %v", got, stats.TotalWait/3)
+	}
+
+	if got := NewClient("secret", WithRateLimit(0, 0)).LimiterStats(); got != (LimiterStats{}) {
+		t.Errorf("LimiterStats without a limiter = %+v, want zero", got)
+	}
+}
@@ -0,0 +1,182 @@
+package notiontest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danecwalker/portfolio/internal/notion"
+)
+
+// minPartSize is the smallest part Notion accepts, except for the last part
+// of a multi-part upload.
+const minPartSize = 5 << 20
+
+// fileUpload is an upload with the parts received so far.
+type fileUpload struct {
+	notion.FileUploadResponse
+	mode  string
+	parts map[int][]byte
+}
+
+// FileUpload returns an upload and its content, joined in part order.
+func (s *Server) FileUpload(uploadId string) (notion.FileUploadResponse, []byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upload, ok := s.uploads[key(uploadId)]
+	if !ok {
+		return notion.FileUploadResponse{}, nil, false
+	}
+	var content bytes.Buffer
+	for part := range len(upload.parts) + 1 {
+		content.Write(upload.parts[part])
+	}
+	return upload.FileUploadResponse, content.Bytes(), true
+}
+
+func (s *Server) createFileUpload(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Mode          string `json:"mode"`
+		Filename      string `json:"filename"`
+		ContentType   string `json:"content_type"`
+		NumberOfParts int    `json:"number_of_parts"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Mode == "" {
+		body.Mode = "single_part"
+	}
+	if body.Mode != "single_part" && body.Mode != "multi_part" {
+		writeError(w, http.StatusBadRequest, notion.ErrCodeValidation, "body.mode should be `single_part` or `multi_part`.")
+		return
+	}
+	if body.Mode == "multi_part" && (body.NumberOfParts < 1 || body.NumberOfParts > 1000) {
+		writeError(w, http.StatusBadRequest, notion.ErrCodeValidation, "body.number_of_parts should be between 1 and 1000.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	expiry := now.Add(time.Hour)
+	upload := &fileUpload{
+		FileUploadResponse: notion.FileUploadResponse{
+			Object:         "file_upload",
+			ID:             s.newID(),
+			CreatedTime:    now,
+			LastEditedTime: now,
+			ExpiryTime:     &expiry,
+			Status:         "pending",
+			Filename:       body.Filename,
+			ContentType:    body.ContentType,
+		},
+		mode:  body.Mode,
+		parts: map[int][]byte{},
+	}
+	upload.UploadURL = s.URL + "/v1/file_uploads/" + upload.ID + "/send"
+	if body.Mode == "multi_part" {
+		upload.CompleteURL = s.URL + "/v1/file_uploads/" + upload.ID + "/complete"
+		upload.NumberOfParts = &notion.NumberOfParts{Total: body.NumberOfParts}
+	}
+	s.uploads[key(upload.ID)] = upload
+	writeJSON(w, upload.FileUploadResponse)
+}
+
+func (s *Server) getFileUpload(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upload, ok := s.uploads[key(r.PathValue("id"))]
+	if !ok {
+		writeNotFound(w, "file upload", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, upload.FileUploadResponse)
+}
+
+// sendFileUpload receives the whole file of a single-part upload, or one
+// part of a multi-part upload.
+func (s *Server) sendFileUpload(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeError(w, http.StatusBadRequest, notion.ErrCodeValidation, "Error parsing multipart body: "+err.Error())
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, notion.ErrCodeValidation, "body.file should be defined.")
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, notion.ErrCodeValidation, err.Error())
+		return
+	}
+	if len(content) > notion.MaxSinglePartSize {
+		writeError(w, http.StatusBadRequest, notion.ErrCodeValidation, "file part is larger than 20 MB.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upload, ok := s.uploads[key(r.PathValue("id"))]
+	if !ok {
+		writeNotFound(w, "file upload", r.PathValue("id"))
+		return
+	}
+	if upload.Status != "pending" {
+		writeError(w, http.StatusBadRequest, notion.ErrCodeValidation, "file upload is not pending.")
+		return
+	}
+
+	part := 0
+	if upload.mode == "multi_part" {
+		part, err = strconv.Atoi(r.FormValue("part_number"))
+		if err != nil || part < 1 || part > upload.NumberOfParts.Total {
+			writeError(w, http.StatusBadRequest, notion.ErrCodeValidation, fmt.Sprintf("part_number should be between 1 and %d.", upload.NumberOfParts.Total))
+			return
+		}
+		if part < upload.NumberOfParts.Total && len(content) < minPartSize {
+			writeError(w, http.StatusBadRequest, notion.ErrCodeValidation, "file parts other than the last should be at least 5 MB.")
+			return
+		}
+		if _, sent := upload.parts[part]; !sent {
+			upload.NumberOfParts.SentCount++
+		}
+	} else {
+		upload.Status = "uploaded"
+		upload.ContentLength = int64(len(content))
+	}
+	upload.parts[part] = content
+	upload.LastEditedTime = time.Now().UTC()
+	writeJSON(w, upload.FileUploadResponse)
+}
+
+func (s *Server) completeFileUpload(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upload, ok := s.uploads[key(r.PathValue("id"))]
+	if !ok {
+		writeNotFound(w, "file upload", r.PathValue("id"))
+		return
+	}
+	if upload.mode != "multi_part" || upload.Status != "pending" {
+		writeError(w, http.StatusBadRequest, notion.ErrCodeValidation, "only pending multi-part uploads can be completed.")
+		return
+	}
+	if upload.NumberOfParts.SentCount != upload.NumberOfParts.Total {
+		writeError(w, http.StatusBadRequest, notion.ErrCodeValidation,
+			fmt.Sprintf("expected %d parts but received %d.", upload.NumberOfParts.Total, upload.NumberOfParts.SentCount))
+		return
+	}
+
+	upload.Status = "uploaded"
+	for _, content := range upload.parts {
+		upload.ContentLength += int64(len(content))
+	}
+	upload.LastEditedTime = time.Now().UTC()
+	writeJSON(w, upload.FileUploadResponse)
+}
@@ -0,0 +1,498 @@
+package notiontest
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/danecwalker/portfolio/internal/notion"
+)
+
+// sortSpec is one entry of a query's sorts.
+type sortSpec struct {
+	Property  string `json:"property"`
+	Timestamp string `json:"timestamp"`
+	Direction string `json:"direction"`
+}
+
+// matches evaluates a query filter, as decoded from JSON, against page.
+// Conditions the fake does not support are reported as errors rather than
+// silently matching.
+func matches(filter map[string]any, page *notion.PageResponse) (bool, error) {
+	if filters, ok := filter["and"]; ok {
+		return matchAll(filters, page, true)
+	}
+	if filters, ok := filter["or"]; ok {
+		return matchAll(filters, page, false)
+	}
+
+	if timestamp, ok := filter["timestamp"].(string); ok {
+		var value time.Time
+		switch timestamp {
+		case "created_time":
+			value = page.CreatedTime
+		case "last_edited_time":
+			value = page.LastEditedTime
+		default:
+			return false, fmt.Errorf("unsupported timestamp filter %q", timestamp)
+		}
+		cond, err := condition(filter, timestamp)
+		if err != nil {
+			return false, err
+		}
+		return matchDate(cond, &value)
+	}
+
+	name, ok := filter["property"].(string)
+	if !ok {
+		return false, fmt.Errorf("filter needs a property, a timestamp, \"and\" or \"or\": %v", filter)
+	}
+	prop, ok := findProperty(page, name)
+	if !ok {
+		return false, fmt.Errorf("Could not find property with name or id: %s", name)
+	}
+
+	for filterType := range filter {
+		if filterType == "property" {
+			continue
+		}
+		cond, err := condition(filter, filterType)
+		if err != nil {
+			return false, err
+		}
+		if filterType == "formula" {
+			return matchFormula(cond, prop)
+		}
+		return matchProperty(filterType, cond, prop)
+	}
+	return false, fmt.Errorf("filter on %q has no condition", name)
+}
+
+func matchAll(filters any, page *notion.PageResponse, all bool) (bool, error) {
+	list, ok := filters.([]any)
+	if !ok {
+		return false, fmt.Errorf("compound filter needs a list, not %v", filters)
+	}
+	for _, f := range list {
+		sub, ok := f.(map[string]any)
+		if !ok {
+			return false, fmt.Errorf("filter needs an object, not %v", f)
+		}
+		ok, err := matches(sub, page)
+		if err != nil {
+			return false, err
+		}
+		if ok != all {
+			return ok, nil
+		}
+	}
+	return all, nil
+}
+
+func condition(filter map[string]any, filterType string) (map[string]any, error) {
+	cond, ok := filter[filterType].(map[string]any)
+	if !ok || len(cond) != 1 {
+		return nil, fmt.Errorf("%s filter needs exactly one condition", filterType)
+	}
+	return cond, nil
+}
+
+// findProperty looks a property up by name, then by ID, as Notion does.
+func findProperty(page *notion.PageResponse, name string) (notion.Property, bool) {
+	if prop, ok := page.Properties[name]; ok {
+		return prop, true
+	}
+	for _, prop := range page.Properties {
+		if prop.ID != "" && prop.ID == name {
+			return prop, true
+		}
+	}
+	return notion.Property{}, false
+}
+
+func matchProperty(filterType string, cond map[string]any, prop notion.Property) (bool, error) {
+	switch filterType {
+	case "title", "rich_text", "url", "email", "phone_number":
+		return matchText(cond, propertyText(prop))
+	case "number", "unique_id":
+		return matchNumber(cond, propertyNumber(prop))
+	case "checkbox":
+		return matchCheckbox(cond, prop.Checkbox)
+	case "select", "status":
+		return matchSelect(cond, propertyText(prop))
+	case "multi_select", "people", "relation":
+		return matchContains(cond, propertyList(prop))
+	case "files":
+		return matchEmpty(cond, len(prop.Files) == 0)
+	case "date", "created_time", "last_edited_time":
+		return matchDate(cond, propertyDate(prop))
+	}
+	return false, fmt.Errorf("unsupported %s filter", filterType)
+}
+
+// matchFormula evaluates a formula filter against the formula's result.
+func matchFormula(cond map[string]any, prop notion.Property) (bool, error) {
+	if prop.Formula == nil {
+		return false, fmt.Errorf("property %s is not a formula", prop.ID)
+	}
+	for resultType, value := range cond {
+		inner, ok := value.(map[string]any)
+		if !ok {
+			return false, fmt.Errorf("formula %s filter needs a condition", resultType)
+		}
+		f := prop.Formula
+		switch resultType {
+		case "string":
+			var text string
+			if f.String != nil {
+				text = *f.String
+			}
+			return matchText(inner, text)
+		case "number":
+			return matchNumber(inner, f.Number)
+		case "checkbox":
+			return matchCheckbox(inner, f.Bool != nil && *f.Bool)
+		case "date":
+			return matchDate(inner, parseDate(f.Date))
+		}
+		return false, fmt.Errorf("unsupported formula %s filter", resultType)
+	}
+	return false, fmt.Errorf("formula filter has no condition")
+}
+
+func matchText(cond map[string]any, text string) (bool, error) {
+	for op, value := range cond {
+		want, _ := value.(string)
+		switch op {
+		case "equals":
+			return text == want, nil
+		case "does_not_equal":
+			return text != want, nil
+		case "contains":
+			return strings.Contains(text, want), nil
+		case "does_not_contain":
+			return !strings.Contains(text, want), nil
+		case "starts_with":
+			return strings.HasPrefix(text, want), nil
+		case "ends_with":
+			return strings.HasSuffix(text, want), nil
+		case "is_empty", "is_not_empty":
+			return matchEmpty(cond, text == "")
+		}
+		return false, fmt.Errorf("unsupported text condition %q", op)
+	}
+	return false, nil
+}
+
+func matchNumber(cond map[string]any, number *float64) (bool, error) {
+	for op, value := range cond {
+		if op == "is_empty" || op == "is_not_empty" {
+			return matchEmpty(cond, number == nil)
+		}
+		want, ok := value.(float64)
+		if !ok {
+			return false, fmt.Errorf("number condition %q needs a number", op)
+		}
+		if number == nil {
+			return op == "does_not_equal", nil
+		}
+		switch op {
+		case "equals":
+			return *number == want, nil
+		case "does_not_equal":
+			return *number != want, nil
+		case "greater_than":
+			return *number > want, nil
+		case "less_than":
+			return *number < want, nil
+		case "greater_than_or_equal_to":
+			return *number >= want, nil
+		case "less_than_or_equal_to":
+			return *number <= want, nil
+		}
+		return false, fmt.Errorf("unsupported number condition %q", op)
+	}
+	return false, nil
+}
+
+func matchCheckbox(cond map[string]any, checked bool) (bool, error) {
+	for op, value := range cond {
+		want, ok := value.(bool)
+		if !ok {
+			return false, fmt.Errorf("checkbox condition %q needs a boolean", op)
+		}
+		switch op {
+		case "equals":
+			return checked == want, nil
+		case "does_not_equal":
+			return checked != want, nil
+		}
+		return false, fmt.Errorf("unsupported checkbox condition %q", op)
+	}
+	return false, nil
+}
+
+func matchSelect(cond map[string]any, option string) (bool, error) {
+	for op, value := range cond {
+		want, _ := value.(string)
+		switch op {
+		case "equals":
+			return option == want, nil
+		case "does_not_equal":
+			return option != want, nil
+		case "is_empty", "is_not_empty":
+			return matchEmpty(cond, option == "")
+		}
+		return false, fmt.Errorf("unsupported select condition %q", op)
+	}
+	return false, nil
+}
+
+func matchContains(cond map[string]any, list []string) (bool, error) {
+	for op, value := range cond {
+		want, _ := value.(string)
+		switch op {
+		case "contains":
+			return slices.Contains(list, want), nil
+		case "does_not_contain":
+			return !slices.Contains(list, want), nil
+		case "is_empty", "is_not_empty":
+			return matchEmpty(cond, len(list) == 0)
+		}
+		return false, fmt.Errorf("unsupported condition %q", op)
+	}
+	return false, nil
+}
+
+func matchEmpty(cond map[string]any, empty bool) (bool, error) {
+	if _, ok := cond["is_empty"]; ok {
+		return empty, nil
+	}
+	if _, ok := cond["is_not_empty"]; ok {
+		return !empty, nil
+	}
+	return false, fmt.Errorf("unsupported condition %v", cond)
+}
+
+// matchDate compares dates by day for equals, and by instant otherwise.
+func matchDate(cond map[string]any, date *time.Time) (bool, error) {
+	now := time.Now()
+	for op, value := range cond {
+		switch op {
+		case "is_empty", "is_not_empty":
+			return matchEmpty(cond, date == nil)
+		case "past_week":
+			return within(date, now.AddDate(0, 0, -7), now), nil
+		case "past_month":
+			return within(date, now.AddDate(0, -1, 0), now), nil
+		case "past_year":
+			return within(date, now.AddDate(-1, 0, 0), now), nil
+		case "next_week":
+			return within(date, now, now.AddDate(0, 0, 7)), nil
+		case "next_month":
+			return within(date, now, now.AddDate(0, 1, 0)), nil
+		case "next_year":
+			return within(date, now, now.AddDate(1, 0, 0)), nil
+		case "this_week":
+			start := now.Truncate(24*time.Hour).AddDate(0, 0, -int(now.Weekday()))
+			return within(date, start, start.AddDate(0, 0, 7)), nil
+		}
+
+		text, _ := value.(string)
+		want := parseDate(&notion.Date{Start: text})
+		if want == nil {
+			return false, fmt.Errorf("date condition %q needs a date, not %v", op, value)
+		}
+		if date == nil {
+			return false, nil
+		}
+		switch op {
+		case "equals":
+			return date.UTC().Format(time.DateOnly) == want.UTC().Format(time.DateOnly), nil
+		case "before":
+			return date.Before(*want), nil
+		case "after":
+			return date.After(*want), nil
+		case "on_or_before":
+			return !date.After(*want), nil
+		case "on_or_after":
+			return !date.Before(*want), nil
+		}
+		return false, fmt.Errorf("unsupported date condition %q", op)
+	}
+	return false, nil
+}
+
+func within(date *time.Time, start time.Time, end time.Time) bool {
+	return date != nil && !date.Before(start) && !date.After(end)
+}
+
+// sortPages orders rows by sorts, keeping the stored order for ties. As in
+// Notion, empty values sort last in either direction.
+func sortPages(rows []notion.PageResponse, sorts []sortSpec) error {
+	for _, s := range sorts {
+		if s.Property == "" && s.Timestamp == "" {
+			return fmt.Errorf("sort needs a property or timestamp")
+		}
+		if s.Direction != "ascending" && s.Direction != "descending" {
+			return fmt.Errorf("sort direction %q should be ascending or descending", s.Direction)
+		}
+	}
+
+	slices.SortStableFunc(rows, func(a, b notion.PageResponse) int {
+		for _, s := range sorts {
+			ka, kb := sortKey(&a, s), sortKey(&b, s)
+			if c := compareKeys(ka, kb, s.Direction == "descending"); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return nil
+}
+
+// sortValue is a comparable form of a property value: a number for numbers,
+// dates and checkboxes, otherwise text.
+type sortValue struct {
+	empty  bool
+	number float64
+	text   string
+}
+
+func sortKey(page *notion.PageResponse, s sortSpec) sortValue {
+	switch s.Timestamp {
+	case "created_time":
+		return sortValue{number: float64(page.CreatedTime.UnixNano())}
+	case "last_edited_time":
+		return sortValue{number: float64(page.LastEditedTime.UnixNano())}
+	}
+
+	prop, ok := findProperty(page, s.Property)
+	if !ok {
+		return sortValue{empty: true}
+	}
+	switch prop.Type {
+	case "number", "unique_id":
+		number := propertyNumber(prop)
+		if number == nil {
+			return sortValue{empty: true}
+		}
+		return sortValue{number: *number}
+	case "checkbox":
+		if prop.Checkbox {
+			return sortValue{number: 1}
+		}
+		return sortValue{}
+	case "date", "created_time", "last_edited_time":
+		date := propertyDate(prop)
+		if date == nil {
+			return sortValue{empty: true}
+		}
+		return sortValue{number: float64(date.UnixNano())}
+	}
+	text := propertyText(prop)
+	return sortValue{empty: text == "", text: text}
+}
+
+func compareKeys(a sortValue, b sortValue, descending bool) int {
+	if a.empty || b.empty {
+		// empty values go last whatever the direction
+		return cmp.Compare(boolRank(a.empty), boolRank(b.empty))
+	}
+	c := cmp.Or(cmp.Compare(a.number, b.number), strings.Compare(a.text, b.text))
+	if descending {
+		return -c
+	}
+	return c
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func propertyText(prop notion.Property) string {
+	var text strings.Builder
+	switch prop.Type {
+	case "title":
+		for _, rt := range prop.Title {
+			text.WriteString(rt.PlainText)
+		}
+	case "rich_text":
+		for _, rt := range prop.RichText {
+			text.WriteString(rt.PlainText)
+		}
+	case "url":
+		return deref(prop.Url)
+	case "email":
+		return deref(prop.Email)
+	case "phone_number":
+		return deref(prop.PhoneNumber)
+	case "select":
+		if prop.Select != nil {
+			return prop.Select.Name
+		}
+	case "status":
+		if prop.Status != nil {
+			return prop.Status.Name
+		}
+	}
+	return text.String()
+}
+
+func propertyNumber(prop notion.Property) *float64 {
+	if prop.Type == "unique_id" && prop.UniqueID != nil && prop.UniqueID.Number != nil {
+		number := float64(*prop.UniqueID.Number)
+		return &number
+	}
+	return prop.Number
+}
+
+func propertyList(prop notion.Property) []string {
+	var list []string
+	for _, option := range prop.MultiSelect {
+		list = append(list, option.Name)
+	}
+	for _, user := range prop.People {
+		list = append(list, user.ID)
+	}
+	for _, relation := range prop.Relation {
+		list = append(list, relation.ID)
+	}
+	return list
+}
+
+func propertyDate(prop notion.Property) *time.Time {
+	switch prop.Type {
+	case "created_time":
+		return prop.CreatedTime
+	case "last_edited_time":
+		return prop.LastEditedTime
+	}
+	return parseDate(prop.Date)
+}
+
+// parseDate reads the start of a date in Notion's date-only or date-time
+// format, or returns nil.
+func parseDate(date *notion.Date) *time.Time {
+	if date == nil {
+		return nil
+	}
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.Parse(layout, date.Start); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,105 @@
+package notiontest
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/danecwalker/portfolio/internal/notion"
+)
+
+var richTextType = reflect.TypeOf(notion.RichText{})
+
+// fillPlainText sets the plain_text Notion computes for every rich text
+// item reachable from v, which must be a pointer, so fixtures and request
+// bodies only need their text content.
+func (s *Server) fillPlainText(v any) {
+	s.walkRichText(reflect.ValueOf(v))
+}
+
+func (s *Server) walkRichText(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			s.walkRichText(v.Elem())
+		}
+	case reflect.Struct:
+		if v.Type() == richTextType {
+			rt := v.Addr().Interface().(*notion.RichText)
+			if rt.PlainText == "" {
+				rt.PlainText = s.plainText(rt)
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				s.walkRichText(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			s.walkRichText(v.Index(i))
+		}
+	case reflect.Map:
+		// map values are not addressable, so each is copied and put back
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(iter.Value().Type()).Elem()
+			value.Set(iter.Value())
+			s.walkRichText(value)
+			v.SetMapIndex(iter.Key(), value)
+		}
+	}
+}
+
+// plainText is the text Notion shows for rt.
+func (s *Server) plainText(rt *notion.RichText) string {
+	switch {
+	case rt.Text != nil:
+		return rt.Text.Content
+	case rt.Equation != nil:
+		return rt.Equation.Expression
+	case rt.Mention != nil:
+		return s.mentionText(rt.Mention)
+	}
+	return ""
+}
+
+func (s *Server) mentionText(mention *notion.Mention) string {
+	switch {
+	case mention.Page != nil:
+		if page, ok := s.pages[key(mention.Page.ID)]; ok {
+			for _, prop := range page.Properties {
+				if prop.Type == "title" {
+					return titleText(prop.Title)
+				}
+			}
+		}
+		return "Untitled"
+	case mention.User != nil:
+		return "@" + mention.User.Name
+	case mention.Date != nil:
+		if mention.Date.End != nil {
+			return mention.Date.Start + " → " + *mention.Date.End
+		}
+		return mention.Date.Start
+	case mention.LinkMention != nil:
+		if mention.LinkMention.Title != "" {
+			return mention.LinkMention.Title
+		}
+		return mention.LinkMention.Href
+	case mention.LinkPreview != nil:
+		return mention.LinkPreview.URL
+	case mention.Database != nil, mention.DataSource != nil:
+		return "Untitled"
+	}
+	return ""
+}
+
+// titleText joins a stored title, whose plain text is already filled in.
+func titleText(title []*notion.RichText) string {
+	var text strings.Builder
+	for _, rt := range title {
+		text.WriteString(rt.PlainText)
+	}
+	return text.String()
+}
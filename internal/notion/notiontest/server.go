@@ -0,0 +1,846 @@
+// Package notiontest provides an in-process fake of the Notion API for
+// testing code built on package notion without a real workspace.
+//
+// The fake serves pages (truncating long properties, which are then read
+// from the page properties endpoint), data sources (queries with filters,
+// sorts and pagination), block children, comments, users and file uploads
+// from in-memory fixtures, and answers unknown objects with Notion-style
+// error bodies. Rich text is
+// given the plain_text Notion would compute, so fixtures built with
+// notion.TitleValue or notion.NewRichText read back as they do from Notion:
+//
+//	srv := notiontest.NewServer()
+//	defer srv.Close()
+//	srv.AddDatasource(notion.DatasourceGetResponse{ID: "links", Properties: schema})
+//	srv.AddPage(row)
+//	client := srv.Client()
+package notiontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danecwalker/portfolio/internal/notion"
+)
+
+// Token is the integration token the fake accepts.
+const Token = "secret_notiontest"
+
+// Server is a fake Notion API. Fixtures may be added while it is serving.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	pages       map[string]*notion.PageResponse
+	pageOrder   []string
+	datasources map[string]*notion.DatasourceGetResponse
+	blocks      map[string]*notion.BlockGetResponse
+	children    map[string][]string
+	comments    map[string][]notion.Comment
+	users       map[string]notion.User
+	uploads     map[string]*fileUpload
+	failures    map[string]*notion.APIError
+	nextID      int
+}
+
+// NewServer starts a fake with no fixtures. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		pages:       map[string]*notion.PageResponse{},
+		datasources: map[string]*notion.DatasourceGetResponse{},
+		blocks:      map[string]*notion.BlockGetResponse{},
+		children:    map[string][]string{},
+		comments:    map[string][]notion.Comment{},
+		users:       map[string]notion.User{},
+		uploads:     map[string]*fileUpload{},
+		failures:    map[string]*notion.APIError{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/pages/{id}", s.getPage)
+	mux.HandleFunc("PATCH /v1/pages/{id}", s.updatePage)
+	mux.HandleFunc("GET /v1/pages/{id}/properties/{propertyId}", s.getPageProperty)
+	mux.HandleFunc("POST /v1/pages", s.createPage)
+	mux.HandleFunc("GET /v1/data_sources/{id}", s.getDatasource)
+	mux.HandleFunc("POST /v1/data_sources/{id}/query", s.queryDatasource)
+	mux.HandleFunc("GET /v1/blocks/{id}", s.getBlock)
+	mux.HandleFunc("GET /v1/blocks/{id}/children", s.listChildren)
+	mux.HandleFunc("PATCH /v1/blocks/{id}/children", s.appendChildren)
+	mux.HandleFunc("GET /v1/comments", s.listComments)
+	mux.HandleFunc("POST /v1/comments", s.createComment)
+	mux.HandleFunc("GET /v1/users", s.listUsers)
+	mux.HandleFunc("GET /v1/users/{id}", s.getUser)
+	mux.HandleFunc("POST /v1/file_uploads", s.createFileUpload)
+	mux.HandleFunc("GET /v1/file_uploads/{id}", s.getFileUpload)
+	mux.HandleFunc("POST /v1/file_uploads/{id}/send", s.sendFileUpload)
+	mux.HandleFunc("POST /v1/file_uploads/{id}/complete", s.completeFileUpload)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusBadRequest, notion.ErrCodeInvalidRequestURL, "Invalid request URL.")
+	})
+
+	s.Server = httptest.NewServer(s.authorize(mux))
+	return s
+}
+
+// Client returns a client for the fake with rate limiting and retries
+// disabled. opts are applied after those defaults.
+func (s *Server) Client(opts ...notion.Option) *notion.Client {
+	defaults := []notion.Option{
+		notion.WithBaseURL(s.URL + "/v1/"),
+		notion.WithRateLimit(0, 0),
+		notion.WithoutRetries(),
+	}
+	return notion.NewClient(Token, append(defaults, opts...)...)
+}
+
+// AddPage stores page. A page whose parent is a data source becomes one of
+// its rows, returned by queries in the order pages were added.
+func (s *Server) AddPage(pages ...notion.PageResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, page := range pages {
+		s.putPage(page)
+	}
+}
+
+// AddDatasource stores the schema of a data source.
+func (s *Server) AddDatasource(datasource notion.DatasourceGetResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if datasource.Properties == nil {
+		datasource.Properties = map[string]notion.PropertySchema{}
+	}
+	s.datasources[key(datasource.ID)] = &datasource
+}
+
+// AddBlocks appends blocks to the children of the page or block parentId.
+// Blocks without an ID are given one.
+func (s *Server) AddBlocks(parentId string, blocks ...notion.BlockGetResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.insertBlocks(parentId, "", blocks)
+}
+
+// AddComments stores comments on the page or block in their Parent.
+func (s *Server) AddComments(comments ...notion.Comment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, comment := range comments {
+		if comment.ID == "" {
+			comment.ID = s.newID()
+		}
+		comment.Object = "comment"
+		s.fillPlainText(&comment.RichText)
+		parent := comment.Parent.PageID
+		if parent == "" {
+			parent = comment.Parent.BlockID
+		}
+		s.comments[key(parent)] = append(s.comments[key(parent)], comment)
+	}
+}
+
+// AddUsers stores workspace users, e.g. the authors of mentions and
+// comments.
+func (s *Server) AddUsers(users ...notion.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range users {
+		user.Object = "user"
+		s.users[key(user.ID)] = user
+	}
+}
+
+// Fail makes every request whose path starts with pathPrefix, e.g.
+// "/v1/data_sources/", answer with err until cleared by passing nil. A
+// RetryAfter is sent as the Retry-After header.
+func (s *Server) Fail(pathPrefix string, err *notion.APIError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		delete(s.failures, pathPrefix)
+		return
+	}
+	s.failures[pathPrefix] = err
+}
+
+// authorize checks the headers every Notion request needs and applies any
+// failures set with Fail.
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+Token {
+			writeError(w, http.StatusUnauthorized, notion.ErrCodeUnauthorized, "API token is invalid.")
+			return
+		}
+		if r.Header.Get("Notion-Version") == "" {
+			writeError(w, http.StatusBadRequest, notion.ErrCodeMissingVersion, "Notion-Version header failed validation.")
+			return
+		}
+
+		s.mu.Lock()
+		var failure *notion.APIError
+		for prefix, err := range s.failures {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				failure = err
+				break
+			}
+		}
+		s.mu.Unlock()
+		if failure != nil {
+			if failure.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(failure.RetryAfter.Seconds()))))
+			}
+			writeError(w, failure.Status, failure.Code, failure.Message)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) getPage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	page, ok := s.pages[key(r.PathValue("id"))]
+	if !ok {
+		writeNotFound(w, "page", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, truncate(page))
+}
+
+// getPageProperty serves title, rich_text, relation and people properties
+// one item at a time, as Notion does, and other properties whole.
+func (s *Server) getPageProperty(w http.ResponseWriter, r *http.Request) {
+	pageSize, ok := queryPageSize(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	page, ok := s.pages[key(r.PathValue("id"))]
+	if !ok {
+		writeNotFound(w, "page", r.PathValue("id"))
+		return
+	}
+	var prop *notion.Property
+	for _, p := range page.Properties {
+		if id, _ := url.PathUnescape(p.ID); id == r.PathValue("propertyId") {
+			prop = &p
+			break
+		}
+	}
+	if prop == nil {
+		writeNotFound(w, "property", r.PathValue("propertyId"))
+		return
+	}
+
+	var values []any
+	switch prop.Type {
+	case "title":
+		for _, rt := range prop.Title {
+			values = append(values, rt)
+		}
+	case "rich_text":
+		for _, rt := range prop.RichText {
+			values = append(values, rt)
+		}
+	case "relation":
+		for _, relation := range prop.Relation {
+			values = append(values, relation)
+		}
+	case "people":
+		for _, user := range prop.People {
+			values = append(values, user)
+		}
+	default:
+		data, _ := json.Marshal(prop)
+		var item map[string]any
+		json.Unmarshal(data, &item)
+		item["object"] = "property_item"
+		writeJSON(w, item)
+		return
+	}
+
+	items := make([]propertyItem, len(values))
+	for i, value := range values {
+		items[i] = propertyItem{
+			cursor: strconv.Itoa(i),
+			value:  map[string]any{"object": "property_item", "id": prop.ID, "type": prop.Type, prop.Type: value},
+		}
+	}
+	body, ok := listBody(w, "property_item", items, func(item propertyItem) string { return item.cursor }, r.URL.Query().Get("start_cursor"), pageSize)
+	if !ok {
+		return
+	}
+	body["property_item"] = map[string]any{"id": prop.ID, "type": prop.Type, prop.Type: map[string]any{}}
+	writeJSON(w, body)
+}
+
+// propertyItem is one entry of a paginated page property. Entries share the
+// property's ID, so their position is the cursor.
+type propertyItem struct {
+	cursor string
+	value  map[string]any
+}
+
+func (item propertyItem) MarshalJSON() ([]byte, error) {
+	return json.Marshal(item.value)
+}
+
+func (s *Server) createPage(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Parent     notion.PageParent          `json:"parent"`
+		Properties map[string]notion.Property `json:"properties"`
+		Icon       *notion.FileObject         `json:"icon"`
+		Cover      *notion.FileObject         `json:"cover"`
+		Children   []blockRequest             `json:"children"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if body.Parent.DataSourceID != "" {
+		if _, ok := s.datasources[key(body.Parent.DataSourceID)]; !ok {
+			writeNotFound(w, "data source", body.Parent.DataSourceID)
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	page := notion.PageResponse{
+		Object:         "page",
+		ID:             s.newID(),
+		CreatedTime:    now,
+		LastEditedTime: now,
+		Properties:     body.Properties,
+		Parent:         body.Parent,
+	}
+	if body.Icon != nil {
+		page.Icon = *body.Icon
+	}
+	if body.Cover != nil {
+		page.Cover = *body.Cover
+	}
+	s.putPage(page)
+	s.insertRequests(page.ID, "", body.Children)
+	writeJSON(w, s.pages[key(page.ID)])
+}
+
+func (s *Server) updatePage(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Properties map[string]notion.Property `json:"properties"`
+		Icon       *notion.FileObject         `json:"icon"`
+		Cover      *notion.FileObject         `json:"cover"`
+		InTrash    *bool                      `json:"in_trash"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	page, ok := s.pages[key(r.PathValue("id"))]
+	if !ok {
+		writeNotFound(w, "page", r.PathValue("id"))
+		return
+	}
+	s.fillPlainText(&body.Properties)
+	for name, prop := range body.Properties {
+		if prop.ID == "" {
+			prop.ID = page.Properties[name].ID
+		}
+		page.Properties[name] = prop
+	}
+	nameIDs(page.Properties)
+	if body.Icon != nil {
+		page.Icon = *body.Icon
+	}
+	if body.Cover != nil {
+		page.Cover = *body.Cover
+	}
+	if body.InTrash != nil {
+		page.InTrash = *body.InTrash
+		page.Archived = *body.InTrash
+	}
+	page.LastEditedTime = time.Now().UTC()
+	writeJSON(w, page)
+}
+
+func (s *Server) getDatasource(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	datasource, ok := s.datasources[key(r.PathValue("id"))]
+	if !ok {
+		writeNotFound(w, "data source", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, struct {
+		Object string `json:"object"`
+		*notion.DatasourceGetResponse
+	}{"data_source", datasource})
+}
+
+func (s *Server) queryDatasource(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Filter      map[string]any `json:"filter"`
+		Sorts       []sortSpec     `json:"sorts"`
+		StartCursor string         `json:"start_cursor"`
+		PageSize    int            `json:"page_size"`
+	}
+	if r.ContentLength != 0 && !decodeBody(w, r, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	datasourceId := r.PathValue("id")
+	if _, ok := s.datasources[key(datasourceId)]; !ok {
+		writeNotFound(w, "data source", datasourceId)
+		return
+	}
+
+	var rows []notion.PageResponse
+	for _, id := range s.pageOrder {
+		page := s.pages[id]
+		if page.InTrash || key(page.Parent.DataSourceID) != key(datasourceId) {
+			continue
+		}
+		if body.Filter != nil {
+			ok, err := matches(body.Filter, page)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, notion.ErrCodeValidation, err.Error())
+				return
+			}
+			if !ok {
+				continue
+			}
+		}
+		rows = append(rows, *page)
+	}
+
+	if err := sortPages(rows, body.Sorts); err != nil {
+		writeError(w, http.StatusBadRequest, notion.ErrCodeValidation, err.Error())
+		return
+	}
+	writeList(w, "page_or_data_source", rows, func(p notion.PageResponse) string { return p.ID }, body.StartCursor, body.PageSize)
+}
+
+func (s *Server) getBlock(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	block, ok := s.blocks[key(r.PathValue("id"))]
+	if !ok {
+		writeNotFound(w, "block", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, block)
+}
+
+func (s *Server) listChildren(w http.ResponseWriter, r *http.Request) {
+	pageSize, ok := queryPageSize(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	parentId := r.PathValue("id")
+	if !s.exists(parentId) {
+		writeNotFound(w, "block", parentId)
+		return
+	}
+
+	var children []notion.BlockGetResponse
+	for _, id := range s.children[key(parentId)] {
+		children = append(children, *s.blocks[id])
+	}
+	writeList(w, "block", children, func(b notion.BlockGetResponse) string { return b.ID }, r.URL.Query().Get("start_cursor"), pageSize)
+}
+
+func (s *Server) appendChildren(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Children []blockRequest `json:"children"`
+		After    string         `json:"after"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if len(body.Children) > 100 {
+		writeError(w, http.StatusBadRequest, notion.ErrCodeValidation, "body.children.length should be ≤ `100`.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	parentId := r.PathValue("id")
+	if !s.exists(parentId) {
+		writeNotFound(w, "block", parentId)
+		return
+	}
+	added := s.insertRequests(parentId, body.After, body.Children)
+	writeList(w, "block", added, func(b notion.BlockGetResponse) string { return b.ID }, "", 100)
+}
+
+func (s *Server) listComments(w http.ResponseWriter, r *http.Request) {
+	pageSize, ok := queryPageSize(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	blockId := r.URL.Query().Get("block_id")
+	if !s.exists(blockId) {
+		writeNotFound(w, "block", blockId)
+		return
+	}
+	writeList(w, "comment", s.comments[key(blockId)], func(c notion.Comment) string { return c.ID }, r.URL.Query().Get("start_cursor"), pageSize)
+}
+
+func (s *Server) createComment(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Parent       notion.PageParent `json:"parent"`
+		DiscussionID string            `json:"discussion_id"`
+		RichText     []notion.RichText `json:"rich_text"`
+		DisplayName  *struct {
+			Type   string `json:"type"`
+			Custom struct {
+				Name string `json:"name"`
+			} `json:"custom"`
+		} `json:"display_name"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	comment := notion.Comment{
+		Object:         "comment",
+		ID:             s.newID(),
+		Parent:         body.Parent,
+		DiscussionID:   body.DiscussionID,
+		CreatedTime:    now,
+		LastEditedTime: now,
+		CreatedBy:      notion.User{Object: "user", ID: "notiontest-bot"},
+		RichText:       body.RichText,
+	}
+	s.fillPlainText(&comment.RichText)
+	if body.DisplayName != nil {
+		comment.DisplayName = &notion.CommentDisplayName{Type: body.DisplayName.Type, ResolvedName: body.DisplayName.Custom.Name}
+	}
+
+	// replies are filed under the page of the discussion they belong to
+	parent := body.Parent.PageID
+	if body.DiscussionID != "" {
+		parent = ""
+		for id, comments := range s.comments {
+			for _, c := range comments {
+				if c.DiscussionID == body.DiscussionID {
+					parent = id
+					comment.Parent = c.Parent
+				}
+			}
+		}
+		if parent == "" {
+			writeNotFound(w, "discussion", body.DiscussionID)
+			return
+		}
+	} else {
+		if !s.exists(parent) {
+			writeNotFound(w, "page", parent)
+			return
+		}
+		comment.DiscussionID = s.newID()
+	}
+	s.comments[key(parent)] = append(s.comments[key(parent)], comment)
+	writeJSON(w, comment)
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	pageSize, ok := queryPageSize(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var users []notion.User
+	for _, user := range s.users {
+		users = append(users, user)
+	}
+	slices.SortFunc(users, func(a, b notion.User) int { return strings.Compare(a.ID, b.ID) })
+	writeList(w, "user", users, func(u notion.User) string { return u.ID }, r.URL.Query().Get("start_cursor"), pageSize)
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[key(r.PathValue("id"))]
+	if !ok {
+		writeNotFound(w, "user", r.PathValue("id"))
+		return
+	}
+	writeJSON(w, user)
+}
+
+// putPage stores page, keeping its place in query order if it was already
+// stored.
+func (s *Server) putPage(page notion.PageResponse) {
+	if page.ID == "" {
+		page.ID = s.newID()
+	}
+	page.Object = "page"
+	if page.Properties == nil {
+		page.Properties = map[string]notion.Property{}
+	}
+	nameIDs(page.Properties)
+	s.fillPlainText(&page)
+	if _, ok := s.pages[key(page.ID)]; !ok {
+		s.pageOrder = append(s.pageOrder, key(page.ID))
+	}
+	s.pages[key(page.ID)] = &page
+}
+
+// nameIDs gives properties without an ID their escaped name as one, so
+// fixtures can leave IDs out.
+func nameIDs(properties map[string]notion.Property) {
+	for name, prop := range properties {
+		if prop.ID == "" {
+			prop.ID = url.PathEscape(name)
+			properties[name] = prop
+		}
+	}
+}
+
+// truncatedLength is how many items of a title, rich_text, relation or
+// people property Notion includes in a page.
+const truncatedLength = 25
+
+// truncate returns page as Notion sends it, with longer properties cut
+// short.
+func truncate(page *notion.PageResponse) notion.PageResponse {
+	cut := *page
+	cut.Properties = make(map[string]notion.Property, len(page.Properties))
+	for name, prop := range page.Properties {
+		prop.Title = prop.Title[:min(len(prop.Title), truncatedLength)]
+		prop.RichText = prop.RichText[:min(len(prop.RichText), truncatedLength)]
+		prop.People = prop.People[:min(len(prop.People), truncatedLength)]
+		if len(prop.Relation) > truncatedLength {
+			prop.Relation = prop.Relation[:truncatedLength]
+			prop.HasMore = true
+		}
+		cut.Properties[name] = prop
+	}
+	return cut
+}
+
+// insertBlocks adds blocks under parentId after the child after, or at the
+// end, returning them with their IDs.
+func (s *Server) insertBlocks(parentId string, after string, blocks []notion.BlockGetResponse) []notion.BlockGetResponse {
+	added := make([]notion.BlockGetResponse, 0, len(blocks))
+	var ids []string
+	for _, block := range blocks {
+		if block.ID == "" {
+			block.ID = s.newID()
+		}
+		block.Object = "block"
+		block.Parent = notion.PageParent{Type: "block_id", BlockID: parentId}
+		if _, ok := s.pages[key(parentId)]; ok {
+			block.Parent = notion.PageParent{Type: "page_id", PageID: parentId}
+		}
+		block.HasChildren = len(s.children[key(block.ID)]) > 0
+		s.fillPlainText(&block)
+		s.blocks[key(block.ID)] = &block
+		ids = append(ids, key(block.ID))
+		added = append(added, block)
+	}
+
+	siblings := s.children[key(parentId)]
+	at := len(siblings)
+	if i := slices.Index(siblings, key(after)); after != "" && i >= 0 {
+		at = i + 1
+	}
+	s.children[key(parentId)] = slices.Insert(siblings, at, ids...)
+	if parent, ok := s.blocks[key(parentId)]; ok && len(ids) > 0 {
+		parent.HasChildren = true
+	}
+	return added
+}
+
+// blockRequest is a block in a request body, whose children are nested in
+// its type-specific content rather than fetched separately.
+type blockRequest struct {
+	block    notion.BlockGetResponse
+	children []blockRequest
+}
+
+func (b *blockRequest) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	var blockType string
+	if err := json.Unmarshal(fields["type"], &blockType); err != nil {
+		return fmt.Errorf("block type: %w", err)
+	}
+
+	if raw, ok := fields[blockType]; ok {
+		var content map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &content); err != nil {
+			return err
+		}
+		if children, ok := content["children"]; ok {
+			if err := json.Unmarshal(children, &b.children); err != nil {
+				return err
+			}
+			delete(content, "children")
+			stripped, err := json.Marshal(content)
+			if err != nil {
+				return err
+			}
+			fields[blockType] = stripped
+		}
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &b.block)
+}
+
+// insertRequests inserts the blocks of a request like insertBlocks, then
+// their nested children under each of them.
+func (s *Server) insertRequests(parentId string, after string, requests []blockRequest) []notion.BlockGetResponse {
+	blocks := make([]notion.BlockGetResponse, len(requests))
+	for i, req := range requests {
+		blocks[i] = req.block
+	}
+	added := s.insertBlocks(parentId, after, blocks)
+	for i, req := range requests {
+		if len(req.children) > 0 {
+			s.insertRequests(added[i].ID, "", req.children)
+			added[i].HasChildren = true
+		}
+	}
+	return added
+}
+
+// exists reports whether id is a stored page or block.
+func (s *Server) exists(id string) bool {
+	_, isPage := s.pages[key(id)]
+	_, isBlock := s.blocks[key(id)]
+	return isPage || isBlock
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.nextID)
+}
+
+// key normalises an ID, which Notion accepts with or without dashes.
+func key(id string) string {
+	return strings.ToLower(strings.ReplaceAll(id, "-", ""))
+}
+
+// writeList writes one page of items, using the ID of the first item of the
+// next page as its cursor.
+func writeList[T any](w http.ResponseWriter, itemType string, items []T, id func(T) string, startCursor string, pageSize int) {
+	if body, ok := listBody(w, itemType, items, id, startCursor, pageSize); ok {
+		writeJSON(w, body)
+	}
+}
+
+// listBody is writeList's body, for lists that carry more fields. It writes
+// the error itself when startCursor is not one of the items.
+func listBody[T any](w http.ResponseWriter, itemType string, items []T, id func(T) string, startCursor string, pageSize int) (map[string]any, bool) {
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 100
+	}
+
+	start := 0
+	if startCursor != "" {
+		start = slices.IndexFunc(items, func(item T) bool { return key(id(item)) == key(startCursor) })
+		if start < 0 {
+			writeError(w, http.StatusBadRequest, notion.ErrCodeValidation, "start_cursor provided is invalid: "+startCursor)
+			return nil, false
+		}
+	}
+
+	end := min(start+pageSize, len(items))
+	results := items[start:end]
+	if results == nil {
+		results = []T{}
+	}
+	var nextCursor *string
+	if end < len(items) {
+		next := id(items[end])
+		nextCursor = &next
+	}
+
+	return map[string]any{
+		"object":      "list",
+		"type":        itemType,
+		"results":     results,
+		"has_more":    nextCursor != nil,
+		"next_cursor": nextCursor,
+	}, true
+}
+
+func queryPageSize(w http.ResponseWriter, r *http.Request) (int, bool) {
+	value := r.URL.Query().Get("page_size")
+	if value == "" {
+		return 0, true
+	}
+	pageSize, err := strconv.Atoi(value)
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		writeError(w, http.StatusBadRequest, notion.ErrCodeValidation, "page_size should be a number between 1 and 100.")
+		return 0, false
+	}
+	return pageSize, true
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, notion.ErrCodeInvalidJSON, "Error parsing JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeNotFound(w http.ResponseWriter, object string, id string) {
+	writeError(w, http.StatusNotFound, notion.ErrCodeObjectNotFound,
+		fmt.Sprintf("Could not find %s with ID: %s. Make sure the relevant pages and databases are shared with your integration.", object, id))
+}
+
+// writeError writes an error body in Notion's format.
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"object":     "error",
+		"status":     status,
+		"code":       code,
+		"message":    message,
+		"request_id": "notiontest",
+	})
+}
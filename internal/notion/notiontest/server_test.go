@@ -0,0 +1,274 @@
+package notiontest_test
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danecwalker/portfolio/internal/notion"
+	"github.com/danecwalker/portfolio/internal/notion/notiontest"
+)
+
+func newServer(t *testing.T) (*notiontest.Server, *notion.Client) {
+	t.Helper()
+	srv := notiontest.NewServer()
+	t.Cleanup(srv.Close)
+	return srv, srv.Client(notion.WithLogger(slog.New(slog.DiscardHandler)))
+}
+
+func TestQuery(t *testing.T) {
+	srv, client := newServer(t)
+	srv.AddDatasource(notion.DatasourceGetResponse{ID: "projects"})
+
+	row := func(name string, stars float64, hidden bool, date time.Time, tags ...string) notion.PageResponse {
+		return notion.PageResponse{
+			Parent: notion.PageParent{Type: "data_source_id", DataSourceID: "projects"},
+			Properties: map[string]notion.Property{
+				"Name":   notion.TitleValue(name),
+				"Stars":  notion.NumberValue(stars),
+				"Hidden": notion.CheckboxValue(hidden),
+				"Date":   notion.DateValue(date),
+				"Tags":   notion.MultiSelectValue(tags...),
+			},
+		}
+	}
+	srv.AddPage(
+		row("portfolio", 12, false, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "go", "svelte"),
+		row("notion client", 40, false, time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), "go"),
+		row("draft", 0, true, time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)),
+		notion.PageResponse{
+			Parent: notion.PageParent{Type: "data_source_id", DataSourceID: "projects"},
+			Properties: map[string]notion.Property{
+				"Name":   notion.TitleValue("undated"),
+				"Stars":  {Type: "number"},
+				"Hidden": notion.CheckboxValue(false),
+				"Date":   {Type: "date"},
+				"Tags":   notion.MultiSelectValue(),
+			},
+		},
+	)
+
+	tests := []struct {
+		name   string
+		filter notion.Filter
+		sorts  []notion.Sort
+		want   []string
+	}{
+		{
+			name: "no filter keeps insertion order",
+			want: []string{"portfolio", "notion client", "draft", "undated"},
+		},
+		{
+			name:   "checkbox",
+			filter: notion.Prop("Hidden").Checkbox().Equals(true),
+			want:   []string{"draft"},
+		},
+		{
+			name:   "title",
+			filter: notion.Prop("Name").Title().StartsWith("no"),
+			want:   []string{"notion client"},
+		},
+		{
+			name:   "number",
+			filter: notion.Prop("Stars").Number().GreaterThan(10),
+			want:   []string{"portfolio", "notion client"},
+		},
+		{
+			name:   "multi_select",
+			filter: notion.Prop("Tags").MultiSelect().Contains("svelte"),
+			want:   []string{"portfolio"},
+		},
+		{
+			name:   "date",
+			filter: notion.Prop("Date").Date().OnOrAfter(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+			want:   []string{"notion client", "draft"},
+		},
+		{
+			name: "or within and",
+			filter: notion.And(
+				notion.Prop("Hidden").Checkbox().Equals(false),
+				notion.Or(
+					notion.Prop("Tags").MultiSelect().Contains("svelte"),
+					notion.Prop("Date").Date().IsEmpty(),
+				),
+			),
+			want: []string{"portfolio", "undated"},
+		},
+		{
+			name:  "sort descending puts empty values last",
+			sorts: []notion.Sort{notion.SortBy("Date").Desc()},
+			want:  []string{"draft", "notion client", "portfolio", "undated"},
+		},
+		{
+			name:   "filter and sort",
+			filter: notion.Prop("Hidden").Checkbox().Equals(false),
+			sorts:  []notion.Sort{notion.SortBy("Stars").Desc()},
+			want:   []string{"notion client", "portfolio", "undated"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := notion.Datasource("projects").Query(tt.filter, tt.sorts...).All().Fetch(client)
+			if err != nil {
+				t.Fatalf("query: %v", err)
+			}
+			var got []string
+			for _, page := range resp.Results {
+				got = append(got, page.Title())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	srv, client := newServer(t)
+	srv.AddPage(notion.PageResponse{ID: "about", Properties: map[string]notion.Property{"title": notion.TitleValue("About me")}})
+	srv.AddBlocks("about", notion.BlockGetResponse{
+		Type: "paragraph",
+		Paragraph: &notion.Paragraph{RichText: []notion.RichText{
+			{Type: "text", Text: &notion.Text{Content: "see "}},
+			{Type: "mention", Mention: &notion.Mention{Type: "page", Page: &notion.Reference{ID: "about"}}},
+			{Type: "equation", Equation: &notion.Equation{Expression: "e=mc^2"}},
+		}},
+	})
+
+	page, err := notion.Page("about").Fetch(client)
+	if err != nil {
+		t.Fatalf("page: %v", err)
+	}
+	if got := page.Title(); got != "About me" {
+		t.Errorf("title = %q, want %q", got, "About me")
+	}
+
+	blocks, err := notion.Blocks("about").Query().Fetch(client)
+	if err != nil {
+		t.Fatalf("blocks: %v", err)
+	}
+	if got := notion.PlainText(blocks.Results[0].Paragraph.RichText); got != "see About mee=mc^2" {
+		t.Errorf("paragraph = %q", got)
+	}
+}
+
+func TestAppendNestedChildren(t *testing.T) {
+	srv, client := newServer(t)
+	srv.AddPage(notion.PageResponse{ID: "notes"})
+
+	toggle := notion.ToggleBlock(notion.NewRichText("more")...).WithChildren(
+		notion.ParagraphBlock(notion.NewRichText("hidden")...),
+		notion.BulletedListItemBlock(notion.NewRichText("item")...).WithChildren(
+			notion.ParagraphBlock(notion.NewRichText("deep")...),
+		),
+	)
+	added, err := notion.Blocks("notes").Append(toggle).Fetch(client)
+	if err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if len(added.Results) != 1 || !added.Results[0].HasChildren {
+		t.Fatalf("append returned %+v, want one toggle with children", added.Results)
+	}
+
+	tree, err := notion.BlockTree("notes").Fetch(client)
+	if err != nil {
+		t.Fatalf("tree: %v", err)
+	}
+	got := tree.Results[0]
+	if got.Type != "toggle" || len(got.Children) != 2 {
+		t.Fatalf("toggle = %+v", got)
+	}
+	if text := notion.PlainText(got.Children[0].Paragraph.RichText); text != "hidden" {
+		t.Errorf("first child = %q, want %q", text, "hidden")
+	}
+	item := got.Children[1]
+	if len(item.Children) != 1 || notion.PlainText(item.Children[0].Paragraph.RichText) != "deep" {
+		t.Errorf("list item children = %+v", item.Children)
+	}
+}
+
+func TestPageProperty(t *testing.T) {
+	srv, client := newServer(t)
+	var notes []*notion.RichText
+	var related []notion.Relation
+	var want strings.Builder
+	for i := range 30 {
+		notes = append(notes, &notion.NewRichText(fmt.Sprintf("%d ", i))[0])
+		related = append(related, notion.Relation{ID: fmt.Sprintf("page-%d", i)})
+		fmt.Fprintf(&want, "%d ", i)
+	}
+	srv.AddPage(notion.PageResponse{ID: "long", Properties: map[string]notion.Property{
+		"Notes":   {Type: "rich_text", RichText: notes},
+		"Related": {Type: "relation", Relation: related},
+		"Stars":   notion.NumberValue(5),
+	}})
+
+	page, err := notion.Page("long").Fetch(client)
+	if err != nil {
+		t.Fatalf("page: %v", err)
+	}
+	if got := len(page.Properties["Notes"].RichText); got != 25 {
+		t.Errorf("page has %d rich text items, want Notion's 25", got)
+	}
+	if related := page.Properties["Related"]; len(related.Relation) != 25 || !related.HasMore {
+		t.Errorf("page relation = %d items, has_more %v, want 25 and true", len(related.Relation), related.HasMore)
+	}
+
+	page, err = notion.Page("long").ExpandProperties().Fetch(client)
+	if err != nil {
+		t.Fatalf("expanded page: %v", err)
+	}
+	var got strings.Builder
+	for _, rt := range page.Properties["Notes"].RichText {
+		got.WriteString(rt.PlainText)
+	}
+	if got.String() != want.String() {
+		t.Errorf("expanded notes = %q, want %q", got.String(), want.String())
+	}
+	if got := len(page.Properties["Related"].Relation); got != 30 {
+		t.Errorf("expanded relation has %d items, want 30", got)
+	}
+
+	prop, err := notion.PageProperty("long", page.Properties["Related"].ID).PageSize(10).Fetch(client)
+	if err != nil {
+		t.Fatalf("relation property: %v", err)
+	}
+	if len(prop.Relation) != 30 || prop.Relation[29].ID != "page-29" {
+		t.Errorf("relation property = %+v", prop.Relation)
+	}
+
+	prop, err = notion.PageProperty("long", page.Properties["Stars"].ID).Fetch(client)
+	if err != nil {
+		t.Fatalf("number property: %v", err)
+	}
+	if prop.Number == nil || *prop.Number != 5 {
+		t.Errorf("number property = %+v", prop)
+	}
+}
+
+func TestFail(t *testing.T) {
+	srv, client := newServer(t)
+	srv.AddPage(notion.PageResponse{ID: "about"})
+
+	srv.Fail("/v1/pages/", &notion.APIError{Status: 429, Code: notion.ErrCodeRateLimited, Message: "slow down", RetryAfter: 2 * time.Second})
+	_, err := notion.Page("about").Fetch(client)
+	if !notion.IsRateLimited(err) {
+		t.Fatalf("error = %v, want rate limited", err)
+	}
+	var apiErr *notion.APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter != 2*time.Second {
+		t.Errorf("RetryAfter = %v, want 2s", apiErr.RetryAfter)
+	}
+
+	srv.Fail("/v1/pages/", nil)
+	if _, err := notion.Page("about").Fetch(client); err != nil {
+		t.Fatalf("after clearing the failure: %v", err)
+	}
+	if _, err := notion.Page("missing").Fetch(client); !notion.IsNotFound(err) {
+		t.Fatalf("error = %v, want not found", err)
+	}
+}
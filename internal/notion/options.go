@@ -0,0 +1,64 @@
+package notion
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a Client created by NewClient.
+type Option func(*Client)
+
+// WithBaseURL points the client at another API root, such as a local fake
+// server in tests.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if !strings.HasSuffix(baseURL, "/") {
+			baseURL += "/"
+		}
+		c.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sends requests through a copy of hc. Its transport is
+// wrapped to add the Notion headers, so hc itself is left untouched. A nil
+// hc keeps the default client.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.httpClient = *hc
+		}
+	}
+}
+
+// WithTransport replaces http.DefaultTransport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.httpClient.Transport = rt
+	}
+}
+
+// WithTimeout limits each HTTP attempt, including reading the body.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithNotionVersion pins the Notion-Version header sent on every request.
+func WithNotionVersion(version string) Option {
+	return func(c *Client) {
+		c.version = version
+	}
+}
+
+// WithLogger sets the logger used for request logging instead of
+// slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
@@ -1,15 +1,20 @@
 package notion
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
+	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
 type PageRequest struct {
 	method string
 	pageId string
+	body   map[string]any
+	expand bool
 }
 
 type Cover struct {
@@ -33,6 +38,20 @@ type PageResponse struct {
 	PublicURL      string              `json:"public_url"`
 }
 
+// Title returns the plain text of the page's title property.
+func (p *PageResponse) Title() string {
+	for _, prop := range p.Properties {
+		if prop.Type == "title" {
+			var title strings.Builder
+			for _, rt := range prop.Title {
+				title.WriteString(rt.PlainText)
+			}
+			return title.String()
+		}
+	}
+	return ""
+}
+
 func Page(pageId string) *PageRequest {
 	return &PageRequest{
 		method: "GET",
@@ -40,23 +59,134 @@ func Page(pageId string) *PageRequest {
 	}
 }
 
+// ExpandProperties makes Fetch retrieve, in full, every title, rich_text,
+// relation, people and rollup property that Notion truncated at 25 items.
+// Each costs at least one extra request.
+func (pr *PageRequest) ExpandProperties() *PageRequest {
+	pr.expand = true
+	return pr
+}
+
+// CreatePage adds a page under a page or data source. Under a data source the
+// properties must match its schema; under a page only "title" is allowed.
+// children may be nil.
+func CreatePage(parent PageParent, properties map[string]Property, children []Block) *PageRequest {
+	body := map[string]any{
+		"parent":     parent,
+		"properties": propertyValues(properties),
+	}
+	if len(children) > 0 {
+		body["children"] = children
+	}
+	return &PageRequest{
+		method: "CREATE",
+		body:   body,
+	}
+}
+
+// Update sets the given properties, leaving the others unchanged. Use the
+// *Value constructors to build them.
+func (pr *PageRequest) Update(properties map[string]Property) *PageRequest {
+	pr.patch("properties", propertyValues(properties))
+	return pr
+}
+
+// Archive moves the page to the trash.
+func (pr *PageRequest) Archive() *PageRequest {
+	pr.patch("in_trash", true)
+	return pr
+}
+
+// Restore brings an archived page back out of the trash.
+func (pr *PageRequest) Restore() *PageRequest {
+	pr.patch("in_trash", false)
+	return pr
+}
+
+// Cover sets the page cover, e.g. to ExternalFile or UploadedFile. It can be
+// used on CreatePage too.
+func (pr *PageRequest) Cover(cover FileObject) *PageRequest {
+	pr.patch("cover", fileValue{FileObject: cover})
+	return pr
+}
+
+// Icon sets the page icon, e.g. to EmojiIcon or UploadedFile. It can be used
+// on CreatePage too.
+func (pr *PageRequest) Icon(icon FileObject) *PageRequest {
+	pr.patch("icon", fileValue{FileObject: icon})
+	return pr
+}
+
+// patch adds a field to the request body, turning a GET into an update.
+func (pr *PageRequest) patch(key string, value any) {
+	if pr.method != "CREATE" {
+		pr.method = "UPDATE"
+	}
+	if pr.body == nil {
+		pr.body = map[string]any{}
+	}
+	pr.body[key] = value
+}
+
 func (pr *PageRequest) Fetch(c *Client) (*PageResponse, error) {
-	url := c.buildURL("pages/" + pr.pageId)
-	fmt.Println(url)
-	req, err := http.NewRequest(pr.method, url, nil)
-	if err != nil {
-		return nil, err
+	return pr.FetchContext(context.Background(), c)
+}
+
+// FetchContext is Fetch with a context that cancels the Notion call, including
+// any rate limit wait or retry backoff.
+func (pr *PageRequest) FetchContext(ctx context.Context, c *Client) (*PageResponse, error) {
+	var reqBody io.Reader
+	var method string
+	var endpoint string
+
+	switch pr.method {
+	case "CREATE":
+		method = http.MethodPost
+		endpoint = "pages"
+	case "UPDATE":
+		method = http.MethodPatch
+		endpoint = "pages/" + pr.pageId
+	default:
+		method = http.MethodGet
+		endpoint = "pages/" + pr.pageId
+	}
+	if pr.body != nil {
+		jsonBytes, err := json.Marshal(pr.body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(jsonBytes)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	url := c.buildURL(endpoint)
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+
 	var pageResp PageResponse
-	err = json.NewDecoder(resp.Body).Decode(&pageResp)
+	err = c.do(req, &pageResp)
 	if err != nil {
 		return nil, err
 	}
+
+	// only retrievals expand; updates and creates return the page as sent
+	if pr.expand && method == http.MethodGet {
+		for name, prop := range pageResp.Properties {
+			if !prop.truncated() {
+				continue
+			}
+			full, err := PageProperty(pageResp.ID, prop.ID).FetchContext(ctx, c)
+			if err != nil {
+				return nil, err
+			}
+			// without a property_item header the list can't be assembled,
+			// so the truncated value is better than an empty one
+			if full.Type == "" {
+				continue
+			}
+			pageResp.Properties[name] = *full
+		}
+	}
 	return &pageResp, nil
 }
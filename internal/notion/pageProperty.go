@@ -0,0 +1,226 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// truncatedLength is how many items Notion includes for title, rich_text,
+// relation, people and rollup values in page responses.
+const truncatedLength = 25
+
+// PagePropertyRequest retrieves one property of a page in full, following
+// every page of the property item list.
+type PagePropertyRequest struct {
+	pageId     string
+	propertyId string
+	pageSize   int
+}
+
+// PageProperty retrieves the property with the given ID, as found in
+// Property.ID, from a page.
+func PageProperty(pageId string, propertyId string) *PagePropertyRequest {
+	return &PagePropertyRequest{
+		pageId:     pageId,
+		propertyId: propertyId,
+	}
+}
+
+// PageSize sets the number of items per request (Notion allows at most 100).
+func (pr *PagePropertyRequest) PageSize(pageSize int) *PagePropertyRequest {
+	pr.pageSize = pageSize
+	return pr
+}
+
+func (pr *PagePropertyRequest) Fetch(c *Client) (*Property, error) {
+	return pr.FetchContext(context.Background(), c)
+}
+
+func (pr *PagePropertyRequest) FetchContext(ctx context.Context, c *Client) (*Property, error) {
+	var header *propertyItem
+	var items []Property
+	startCursor := ""
+	for {
+		raw, err := pr.fetchPage(ctx, c, startCursor)
+		if err != nil {
+			return nil, err
+		}
+
+		var head struct {
+			Object string `json:"object"`
+		}
+		if err := json.Unmarshal(raw, &head); err != nil {
+			return nil, err
+		}
+		if head.Object == "property_item" {
+			// single-value properties come back whole, in the page's shape
+			var prop Property
+			if err := json.Unmarshal(raw, &prop); err != nil {
+				return nil, err
+			}
+			return &prop, nil
+		}
+
+		var list propertyItemList
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+		header = list.PropertyItem
+		for _, item := range list.Results {
+			prop, err := item.property()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, prop)
+		}
+
+		next, ok := list.Pagination.next()
+		if !ok {
+			break
+		}
+		startCursor = next
+	}
+
+	return assembleProperty(header, items), nil
+}
+
+func (pr *PagePropertyRequest) fetchPage(ctx context.Context, c *Client, startCursor string) (json.RawMessage, error) {
+	query := url.Values{}
+	if startCursor != "" {
+		query.Set("start_cursor", startCursor)
+	}
+	if pr.pageSize > 0 {
+		query.Set("page_size", strconv.Itoa(pr.pageSize))
+	}
+
+	// property IDs are returned already escaped
+	reqURL := c.buildURL("pages/" + pr.pageId + "/properties/" + pr.propertyId)
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	err = c.do(req, &raw)
+	if err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+type propertyItemList struct {
+	Results      []propertyItem `json:"results"`
+	PropertyItem *propertyItem  `json:"property_item"`
+	Pagination
+}
+
+// propertyItem is one entry of a paginated property. Unlike in a page,
+// each holds a single rich text, relation or person.
+type propertyItem struct {
+	ID       string    `json:"id"`
+	Type     string    `json:"type"`
+	Title    *RichText `json:"title,omitempty"`
+	RichText *RichText `json:"rich_text,omitempty"`
+	Relation *Relation `json:"relation,omitempty"`
+	People   *User     `json:"people,omitempty"`
+	Rollup   *Rollup   `json:"rollup,omitempty"`
+	raw      json.RawMessage
+}
+
+func (pi *propertyItem) UnmarshalJSON(data []byte) error {
+	type plain propertyItem
+	if err := json.Unmarshal(data, (*plain)(pi)); err != nil {
+		return err
+	}
+	pi.raw = data
+	return nil
+}
+
+// property converts a list entry into a one-item Property. Rollups list
+// entries of any type, so anything else is decoded as a whole property.
+func (pi *propertyItem) property() (Property, error) {
+	prop := Property{ID: pi.ID, Type: pi.Type}
+	switch {
+	case pi.Title != nil:
+		prop.Title = []*RichText{pi.Title}
+	case pi.RichText != nil:
+		prop.RichText = []*RichText{pi.RichText}
+	case pi.Relation != nil:
+		prop.Relation = []Relation{*pi.Relation}
+	case pi.People != nil:
+		prop.People = []User{*pi.People}
+	default:
+		if err := json.Unmarshal(pi.raw, &prop); err != nil {
+			return Property{}, err
+		}
+	}
+	return prop, nil
+}
+
+// assembleProperty joins list entries back into the value a page response
+// would have held if it hadn't been truncated.
+func assembleProperty(header *propertyItem, items []Property) *Property {
+	prop := &Property{}
+	if header != nil {
+		prop.ID = header.ID
+		prop.Type = header.Type
+	}
+
+	switch prop.Type {
+	case "title":
+		prop.Title = []*RichText{}
+		for _, item := range items {
+			prop.Title = append(prop.Title, item.Title...)
+		}
+	case "rich_text":
+		prop.RichText = []*RichText{}
+		for _, item := range items {
+			prop.RichText = append(prop.RichText, item.RichText...)
+		}
+	case "relation":
+		prop.Relation = []Relation{}
+		for _, item := range items {
+			prop.Relation = append(prop.Relation, item.Relation...)
+		}
+	case "people":
+		prop.People = []User{}
+		for _, item := range items {
+			prop.People = append(prop.People, item.People...)
+		}
+	case "rollup":
+		rollup := Rollup{}
+		if header.Rollup != nil {
+			rollup = *header.Rollup
+		}
+		if len(items) > 0 {
+			rollup.Type = "array"
+			rollup.Array = items
+		}
+		prop.Rollup = &rollup
+	}
+	return prop
+}
+
+// truncated reports whether a page response may have cut the property short.
+func (p Property) truncated() bool {
+	switch p.Type {
+	case "relation":
+		return p.HasMore
+	case "title":
+		return len(p.Title) >= truncatedLength
+	case "rich_text":
+		return len(p.RichText) >= truncatedLength
+	case "people":
+		return len(p.People) >= truncatedLength
+	case "rollup":
+		return p.Rollup != nil && p.Rollup.Type == "array" && len(p.Rollup.Array) >= truncatedLength
+	default:
+		return false
+	}
+}
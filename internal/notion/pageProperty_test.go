@@ -0,0 +1,116 @@
+package notion
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPropertyItem(t *testing.T) {
+	three := 3.0
+
+	tests := []struct {
+		name string
+		item string
+		want Property
+	}{
+		{
+			name: "title",
+			item: `{"object":"property_item","id":"title","type":"title","title":{"type":"text","text":{"content":"Hi"},"plain_text":"Hi"}}`,
+			want: Property{ID: "title", Type: "title", Title: []*RichText{{Type: "text", Text: &Text{Content: "Hi"}, PlainText: "Hi"}}},
+		},
+		{
+			name: "rich text",
+			item: `{"object":"property_item","id":"abc","type":"rich_text","rich_text":{"type":"text","text":{"content":"Hi"},"plain_text":"Hi"}}`,
+			want: Property{ID: "abc", Type: "rich_text", RichText: []*RichText{{Type: "text", Text: &Text{Content: "Hi"}, PlainText: "Hi"}}},
+		},
+		{
+			name: "relation",
+			item: `{"object":"property_item","id":"rel","type":"relation","relation":{"id":"page-1"}}`,
+			want: Property{ID: "rel", Type: "relation", Relation: []Relation{{ID: "page-1"}}},
+		},
+		{
+			name: "people",
+			item: `{"object":"property_item","id":"ppl","type":"people","people":{"object":"user","id":"user-1"}}`,
+			want: Property{ID: "ppl", Type: "people", People: []User{{Object: "user", ID: "user-1"}}},
+		},
+		{
+			name: "rollup entry of another type",
+			item: `{"object":"property_item","id":"num","type":"number","number":3}`,
+			want: Property{ID: "num", Type: "number", Number: &three},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var item propertyItem
+			if err := json.Unmarshal([]byte(tt.item), &item); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			got, err := item.property()
+			if err != nil {
+				t.Fatalf("property: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssembleProperty(t *testing.T) {
+	word := func(s string) *RichText { return &RichText{Type: "text", PlainText: s} }
+	one, two := 1.0, 2.0
+
+	tests := []struct {
+		name   string
+		header *propertyItem
+		items  []Property
+		want   *Property
+	}{
+		{
+			name:   "title",
+			header: &propertyItem{ID: "title", Type: "title"},
+			items:  []Property{{Title: []*RichText{word("a")}}, {Title: []*RichText{word("b")}}},
+			want:   &Property{ID: "title", Type: "title", Title: []*RichText{word("a"), word("b")}},
+		},
+		{
+			name:   "empty rich text",
+			header: &propertyItem{ID: "abc", Type: "rich_text"},
+			want:   &Property{ID: "abc", Type: "rich_text", RichText: []*RichText{}},
+		},
+		{
+			name:   "relation",
+			header: &propertyItem{ID: "rel", Type: "relation"},
+			items:  []Property{{Relation: []Relation{{ID: "p1"}}}, {Relation: []Relation{{ID: "p2"}}}},
+			want:   &Property{ID: "rel", Type: "relation", Relation: []Relation{{ID: "p1"}, {ID: "p2"}}},
+		},
+		{
+			name:   "people",
+			header: &propertyItem{ID: "ppl", Type: "people"},
+			items:  []Property{{People: []User{{ID: "u1"}}}},
+			want:   &Property{ID: "ppl", Type: "people", People: []User{{ID: "u1"}}},
+		},
+		{
+			name:   "rollup keeps the function",
+			header: &propertyItem{ID: "roll", Type: "rollup", Rollup: &Rollup{Type: "array", Function: "show_original"}},
+			items:  []Property{{Type: "number", Number: &one}, {Type: "number", Number: &two}},
+			want: &Property{ID: "roll", Type: "rollup", Rollup: &Rollup{
+				Type:     "array",
+				Function: "show_original",
+				Array:    []Property{{Type: "number", Number: &one}, {Type: "number", Number: &two}},
+			}},
+		},
+		{
+			name:  "no header",
+			items: []Property{{Title: []*RichText{word("a")}}},
+			want:  &Property{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := assembleProperty(tt.header, tt.items); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package notion
+
+// Pagination holds the cursor fields Notion returns on every paginated list.
+type Pagination struct {
+	HasMore    bool    `json:"has_more"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// cursor holds the paging options shared by the request builders.
+type cursor struct {
+	startCursor string
+	pageSize    int
+	all         bool
+}
+
+// next reports the cursor to continue from, or false once the last page has
+// been read.
+func (p Pagination) next() (string, bool) {
+	if !p.HasMore || p.NextCursor == nil {
+		return "", false
+	}
+	return *p.NextCursor, true
+}
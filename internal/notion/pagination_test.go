@@ -0,0 +1,146 @@
+package notion
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// newPagingClient returns a client for a server with n data source rows and
+// n child blocks, paged by start_cursor and page_size as Notion pages them.
+func newPagingClient(t *testing.T, n int) *Client {
+	t.Helper()
+	page := func(w http.ResponseWriter, startCursor string, pageSize int, item func(i int) any) {
+		start := 0
+		if startCursor != "" {
+			start, _ = strconv.Atoi(startCursor)
+		}
+		if pageSize <= 0 {
+			pageSize = 100
+		}
+		end := min(start+pageSize, n)
+		results := []any{}
+		for i := start; i < end; i++ {
+			results = append(results, item(i))
+		}
+		body := map[string]any{"results": results, "has_more": end < n, "next_cursor": nil}
+		if end < n {
+			body["next_cursor"] = strconv.Itoa(end)
+		}
+		json.NewEncoder(w).Encode(body)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/data_sources/rows/query", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			StartCursor string `json:"start_cursor"`
+			PageSize    int    `json:"page_size"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		page(w, body.StartCursor, body.PageSize, func(i int) any {
+			return map[string]any{"object": "page", "id": fmt.Sprintf("row-%d", i)}
+		})
+	})
+	mux.HandleFunc("GET /v1/blocks/parent/children", func(w http.ResponseWriter, r *http.Request) {
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+		page(w, r.URL.Query().Get("start_cursor"), pageSize, func(i int) any {
+			return map[string]any{"object": "block", "id": fmt.Sprintf("block-%d", i), "type": "paragraph"}
+		})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return NewClient("secret",
+		WithBaseURL(srv.URL+"/v1/"),
+		WithRateLimit(0, 0),
+		WithLogger(slog.New(slog.DiscardHandler)),
+	)
+}
+
+func TestAll(t *testing.T) {
+	c := newPagingClient(t, 5)
+
+	tests := []struct {
+		name        string
+		fetch       func() ([]string, bool, error)
+		want        []string
+		wantHasMore bool
+	}{
+		{
+			name: "query one page",
+			fetch: func() ([]string, bool, error) {
+				resp, err := Datasource("rows").Query(nil).PageSize(2).Fetch(c)
+				if err != nil {
+					return nil, false, err
+				}
+				return pageIDs(resp.Results), resp.HasMore, nil
+			},
+			want:        []string{"row-0", "row-1"},
+			wantHasMore: true,
+		},
+		{
+			name: "query from a cursor",
+			fetch: func() ([]string, bool, error) {
+				resp, err := Datasource("rows").Query(nil).StartCursor("3").Fetch(c)
+				if err != nil {
+					return nil, false, err
+				}
+				return pageIDs(resp.Results), resp.HasMore, nil
+			},
+			want: []string{"row-3", "row-4"},
+		},
+		{
+			name: "query all",
+			fetch: func() ([]string, bool, error) {
+				resp, err := Datasource("rows").Query(nil).PageSize(2).All().Fetch(c)
+				if err != nil {
+					return nil, false, err
+				}
+				return pageIDs(resp.Results), resp.HasMore, nil
+			},
+			want: []string{"row-0", "row-1", "row-2", "row-3", "row-4"},
+		},
+		{
+			name: "block children all",
+			fetch: func() ([]string, bool, error) {
+				resp, err := Blocks("parent").Query().PageSize(2).All().Fetch(c)
+				if err != nil {
+					return nil, false, err
+				}
+				var ids []string
+				for _, block := range resp.Results {
+					ids = append(ids, block.ID)
+				}
+				return ids, resp.HasMore, nil
+			},
+			want: []string{"block-0", "block-1", "block-2", "block-3", "block-4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, hasMore, err := tt.fetch()
+			if err != nil {
+				t.Fatalf("fetch: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if hasMore != tt.wantHasMore {
+				t.Errorf("has_more = %v, want %v", hasMore, tt.wantHasMore)
+			}
+		})
+	}
+}
+
+func pageIDs(pages []PageResponse) []string {
+	var ids []string
+	for _, page := range pages {
+		ids = append(ids, page.ID)
+	}
+	return ids
+}
@@ -1,11 +1,28 @@
 package notion
 
+// PageParent is where a page, block or comment lives. Type says which ID is
+// set; pages in a data source also carry the database ID.
 type PageParent struct {
-	Type   string `json:"type"`
-	PageID string `json:"page_id"`
+	Type         string `json:"type"`
+	PageID       string `json:"page_id,omitempty"`
+	DataSourceID string `json:"data_source_id,omitempty"`
+	DatabaseID   string `json:"database_id,omitempty"`
+	BlockID      string `json:"block_id,omitempty"`
+	Workspace    bool   `json:"workspace,omitempty"`
+}
+
+// ParentPage places a new page under another page.
+func ParentPage(pageId string) PageParent {
+	return PageParent{Type: "page_id", PageID: pageId}
 }
 
+// ParentDatasource places a new page in a data source as a row.
+func ParentDatasource(datasourceId string) PageParent {
+	return PageParent{Type: "data_source_id", DataSourceID: datasourceId}
+}
+
+// DatasourceParent is the database a data source belongs to.
 type DatasourceParent struct {
-	Type         string `json:"type"`
-	DatasourceID string `json:"database_id"`
+	Type       string `json:"type"`
+	DatabaseID string `json:"database_id"`
 }
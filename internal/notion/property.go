@@ -1,23 +1,39 @@
 package notion
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Property struct {
-	ID           string       `json:"id"`
-	Type         string       `json:"type"`
-	Checkbox     bool         `json:"checkbox,omitempty"`
-	CreatedBy    *User        `json:"created_by,omitempty"`
-	CreatedTime  *time.Time   `json:"created_time,omitempty"`
-	Date         *Date        `json:"date,omitempty"`
-	Email        *string      `json:"email,omitempty"`
-	Files        []FileObject `json:"files,omitempty"`
-	Formula      *Formula     `json:"formula,omitempty"`
-	Icon         any          `json:"icon,omitempty"`
-	LastEditedBy *User        `json:"last_edited_by,omitempty"`
-	Title        []*RichText  `json:"title,omitempty"`
-	RichText     []*RichText  `json:"rich_text,omitempty"`
-	Url          *string      `json:"url,omitempty"`
-	Select       *Select      `json:"select,omitempty"`
+	ID             string        `json:"id"`
+	Type           string        `json:"type"`
+	Button         *Button       `json:"button,omitempty"`
+	Checkbox       bool          `json:"checkbox,omitempty"`
+	CreatedBy      *User         `json:"created_by,omitempty"`
+	CreatedTime    *time.Time    `json:"created_time,omitempty"`
+	Date           *Date         `json:"date,omitempty"`
+	Email          *string       `json:"email,omitempty"`
+	Files          []FileObject  `json:"files,omitempty"`
+	Formula        *Formula      `json:"formula,omitempty"`
+	Icon           any           `json:"icon,omitempty"`
+	LastEditedBy   *User         `json:"last_edited_by,omitempty"`
+	LastEditedTime *time.Time    `json:"last_edited_time,omitempty"`
+	MultiSelect    []Select      `json:"multi_select,omitempty"`
+	Number         *float64      `json:"number,omitempty"`
+	People         []User        `json:"people,omitempty"`
+	PhoneNumber    *string       `json:"phone_number,omitempty"`
+	Relation       []Relation    `json:"relation,omitempty"`
+	Rollup         *Rollup       `json:"rollup,omitempty"`
+	Title          []*RichText   `json:"title,omitempty"`
+	RichText       []*RichText   `json:"rich_text,omitempty"`
+	Url            *string       `json:"url,omitempty"`
+	Select         *Select       `json:"select,omitempty"`
+	Status         *Select       `json:"status,omitempty"`
+	UniqueID       *UniqueID     `json:"unique_id,omitempty"`
+	Verification   *Verification `json:"verification,omitempty"`
+	// HasMore is set on relation properties with more than 25 related pages.
+	HasMore bool `json:"has_more,omitempty"`
 }
 
 type Date struct {
@@ -34,30 +50,112 @@ type Formula struct {
 	String *string  `json:"string,omitempty"`
 }
 
+// RichText is a run of text, a mention or an inline equation; Type says
+// which. PlainText and Href are filled in for every type.
 type RichText struct {
 	Type        string      `json:"type"`
 	Text        *Text       `json:"text,omitempty"`
+	Mention     *Mention    `json:"mention,omitempty"`
+	Equation    *Equation   `json:"equation,omitempty"`
 	PlainText   string      `json:"plain_text,omitempty"`
 	Href        *string     `json:"href,omitempty"`
 	Annotations Annotations `json:"annotations,omitempty"`
 }
 
+// PlainText joins the plain text of every item.
+func PlainText(richText []RichText) string {
+	var text strings.Builder
+	for _, rt := range richText {
+		text.WriteString(rt.PlainText)
+	}
+	return text.String()
+}
+
 type Annotations struct {
 	Bold          bool   `json:"bold"`
 	Italic        bool   `json:"italic"`
 	Strikethrough bool   `json:"strikethrough"`
 	Underline     bool   `json:"underline"`
 	Code          bool   `json:"code"`
-	Color         string `json:"color"`
+	Color         string `json:"color,omitempty"`
 }
 
 type Text struct {
-	Content string  `json:"content"`
-	Link    *string `json:"link,omitempty"`
+	Content string `json:"content"`
+	Link    *Link  `json:"link,omitempty"`
+}
+
+type Link struct {
+	URL string `json:"url"`
+}
+
+// Mention is an inline reference to a page, database, user, date or link.
+// Type says which field is set.
+type Mention struct {
+	Type            string           `json:"type"`
+	Database        *Reference       `json:"database,omitempty"`
+	DataSource      *Reference       `json:"data_source,omitempty"`
+	Date            *Date            `json:"date,omitempty"`
+	LinkMention     *LinkMention     `json:"link_mention,omitempty"`
+	LinkPreview     *LinkPreview     `json:"link_preview,omitempty"`
+	Page            *Reference       `json:"page,omitempty"`
+	TemplateMention *TemplateMention `json:"template_mention,omitempty"`
+	User            *User            `json:"user,omitempty"`
 }
 
+type Reference struct {
+	ID string `json:"id"`
+}
+
+// LinkMention is a pasted URL that Notion unfurled into an inline mention.
+type LinkMention struct {
+	Href        string `json:"href"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	IconURL     string `json:"icon_url,omitempty"`
+}
+
+// TemplateMention is a placeholder in a template that becomes a date
+// ("today", "now") or user ("me") when the template is used.
+type TemplateMention struct {
+	Type                string `json:"type"`
+	TemplateMentionDate string `json:"template_mention_date,omitempty"`
+	TemplateMentionUser string `json:"template_mention_user,omitempty"`
+}
+
+// Select is an option of a select, multi_select or status property.
 type Select struct {
-	ID    string `json:"id"`
+	ID    string `json:"id,omitempty"`
 	Name  string `json:"name"`
-	Color string `json:"color"`
+	Color string `json:"color,omitempty"`
+}
+
+type Button struct{}
+
+type Relation struct {
+	ID string `json:"id"`
+}
+
+// Rollup holds the aggregated value of a rollup property. Type is "number",
+// "date" or "array"; array items are decoded as the rolled-up property type.
+// Rollups Notion can't compute report "incomplete" or "unsupported".
+type Rollup struct {
+	Type     string     `json:"type"`
+	Function string     `json:"function"`
+	Number   *float64   `json:"number,omitempty"`
+	Date     *Date      `json:"date,omitempty"`
+	Array    []Property `json:"array,omitempty"`
+}
+
+type UniqueID struct {
+	Number *int    `json:"number"`
+	Prefix *string `json:"prefix"`
+}
+
+// Verification is the state of a wiki page's verification property:
+// "verified", "expired" or "unverified".
+type Verification struct {
+	State      string `json:"state"`
+	VerifiedBy *User  `json:"verified_by,omitempty"`
+	Date       *Date  `json:"date,omitempty"`
 }
@@ -0,0 +1,219 @@
+package notion
+
+import (
+	"encoding/json"
+	"time"
+	"unicode/utf8"
+)
+
+// maxTextLength is the most characters Notion accepts in one rich text item.
+const maxTextLength = 2000
+
+// propertyValue is a property in the shape Notion expects when creating or
+// updating a page: the value under the key named by Type. Property itself
+// keeps the response shape.
+type propertyValue Property
+
+// propertyValues converts properties for a create or update request body.
+func propertyValues(properties map[string]Property) map[string]propertyValue {
+	values := make(map[string]propertyValue, len(properties))
+	for name, prop := range properties {
+		values[name] = propertyValue(prop)
+	}
+	return values
+}
+
+// MarshalJSON writes a nil value as null, which clears the property.
+func (p propertyValue) MarshalJSON() ([]byte, error) {
+	body := map[string]any{"type": p.Type}
+	if p.ID != "" {
+		body["id"] = p.ID
+	}
+
+	var value any
+	switch p.Type {
+	case "button":
+		value = p.Button
+	case "checkbox":
+		value = p.Checkbox
+	case "created_by":
+		value = p.CreatedBy
+	case "created_time":
+		value = p.CreatedTime
+	case "date":
+		value = p.Date
+	case "email":
+		value = p.Email
+	case "files":
+		files := []fileValue{}
+		for _, file := range p.Files {
+			files = append(files, fileValue{FileObject: file, named: true})
+		}
+		value = files
+	case "formula":
+		value = p.Formula
+	case "last_edited_by":
+		value = p.LastEditedBy
+	case "last_edited_time":
+		value = p.LastEditedTime
+	case "multi_select":
+		value = emptyIfNil(p.MultiSelect)
+	case "number":
+		value = p.Number
+	case "people":
+		value = emptyIfNil(p.People)
+	case "phone_number":
+		value = p.PhoneNumber
+	case "relation":
+		value = emptyIfNil(p.Relation)
+	case "rich_text":
+		value = emptyIfNil(p.RichText)
+	case "rollup":
+		value = p.Rollup
+	case "select":
+		value = p.Select
+	case "status":
+		value = p.Status
+	case "title":
+		value = emptyIfNil(p.Title)
+	case "unique_id":
+		value = p.UniqueID
+	case "url":
+		value = p.Url
+	case "verification":
+		value = p.Verification
+	}
+	if p.Type != "" {
+		body[p.Type] = value
+	}
+	return json.Marshal(body)
+}
+
+// emptyIfNil keeps list values from being written as null, which Notion
+// rejects for list properties.
+func emptyIfNil[T any](values []T) []T {
+	if values == nil {
+		return []T{}
+	}
+	return values
+}
+
+// NewRichText returns plain text as rich text, split into as many items as
+// Notion's per-item length limit requires.
+func NewRichText(content string) []RichText {
+	var richText []RichText
+	for content != "" {
+		chunk := content
+		if utf8.RuneCountInString(chunk) > maxTextLength {
+			n := 0
+			for i := range chunk {
+				if n == maxTextLength {
+					chunk = chunk[:i]
+					break
+				}
+				n++
+			}
+		}
+		content = content[len(chunk):]
+		richText = append(richText, RichText{
+			Type: "text",
+			Text: &Text{Content: chunk},
+		})
+	}
+	return richText
+}
+
+func newRichTextRefs(content string) []*RichText {
+	var refs []*RichText
+	for _, rt := range NewRichText(content) {
+		refs = append(refs, &rt)
+	}
+	return refs
+}
+
+func TitleValue(text string) Property {
+	return Property{Type: "title", Title: newRichTextRefs(text)}
+}
+
+func RichTextValue(text string) Property {
+	return Property{Type: "rich_text", RichText: newRichTextRefs(text)}
+}
+
+func NumberValue(number float64) Property {
+	return Property{Type: "number", Number: &number}
+}
+
+func CheckboxValue(checked bool) Property {
+	return Property{Type: "checkbox", Checkbox: checked}
+}
+
+// SelectValue picks an option by name; Notion creates it if it doesn't
+// exist yet.
+func SelectValue(option string) Property {
+	return Property{Type: "select", Select: &Select{Name: option}}
+}
+
+func MultiSelectValue(options ...string) Property {
+	selected := []Select{}
+	for _, option := range options {
+		selected = append(selected, Select{Name: option})
+	}
+	return Property{Type: "multi_select", MultiSelect: selected}
+}
+
+func StatusValue(status string) Property {
+	return Property{Type: "status", Status: &Select{Name: status}}
+}
+
+// DateValue sets a single date. Times at midnight are written as a date
+// without a time.
+func DateValue(start time.Time) Property {
+	return Property{Type: "date", Date: &Date{Start: formatDateTime(start)}}
+}
+
+func DateRangeValue(start time.Time, end time.Time) Property {
+	endValue := formatDateTime(end)
+	return Property{Type: "date", Date: &Date{Start: formatDateTime(start), End: &endValue}}
+}
+
+func formatDateTime(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		return t.Format(time.DateOnly)
+	}
+	return t.Format(time.RFC3339)
+}
+
+func URLValue(url string) Property {
+	return Property{Type: "url", Url: &url}
+}
+
+func EmailValue(email string) Property {
+	return Property{Type: "email", Email: &email}
+}
+
+func PhoneNumberValue(phoneNumber string) Property {
+	return Property{Type: "phone_number", PhoneNumber: &phoneNumber}
+}
+
+// RelationValue replaces the related pages.
+func RelationValue(pageIds ...string) Property {
+	relations := []Relation{}
+	for _, id := range pageIds {
+		relations = append(relations, Relation{ID: id})
+	}
+	return Property{Type: "relation", Relation: relations}
+}
+
+// PeopleValue replaces the people with the given user IDs.
+func PeopleValue(userIds ...string) Property {
+	people := []User{}
+	for _, id := range userIds {
+		people = append(people, User{Object: "user", ID: id})
+	}
+	return Property{Type: "people", People: people}
+}
+
+// FilesValue replaces the attached files. Each needs a Name.
+func FilesValue(files ...FileObject) Property {
+	return Property{Type: "files", Files: emptyIfNil(files)}
+}
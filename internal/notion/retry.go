@@ -0,0 +1,98 @@
+package notion
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how the client retries requests that Notion
+// rate-limited (429) or failed with a transient gateway error (502, 503,
+// 504). Only idempotent requests and data source queries are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of attempts made after the first one.
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry; it doubles on every
+	// further attempt and is jittered.
+	BaseDelay time.Duration
+	// MaxDelay caps a single wait. A Retry-After longer than this is not
+	// waited out and the error is returned instead.
+	MaxDelay time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithoutRetries makes every request a single attempt.
+func WithoutRetries() Option {
+	return func(c *Client) {
+		c.retry = RetryPolicy{}
+	}
+}
+
+// retryable reports whether req can safely be sent again. Data source
+// queries and searches are POSTs but never modify anything.
+func retryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return strings.HasSuffix(req.URL.Path, "/query") || strings.HasSuffix(req.URL.Path, "/search")
+	default:
+		return false
+	}
+}
+
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns how long to wait before retry number attempt (starting at
+// 0), preferring the server's Retry-After when it sent one.
+func (p RetryPolicy) backoff(attempt int, retryAfter time.Duration) (time.Duration, bool) {
+	if retryAfter > 0 {
+		return retryAfter, p.MaxDelay <= 0 || retryAfter <= p.MaxDelay
+	}
+
+	delay := p.BaseDelay << attempt
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0, true
+	}
+	// jitter keeps concurrent handlers from retrying in lockstep
+	return delay/2 + rand.N(delay/2+1), true
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as
+// an HTTP date.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
@@ -0,0 +1,192 @@
+package notion
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// reply is one canned response of a retry test server.
+type reply struct {
+	status     int
+	retryAfter string
+}
+
+// newRetryServer answers with replies in order, repeating the last one, and
+// counts the requests it receives.
+func newRetryServer(t *testing.T, replies []reply) (*Client, *atomic.Int32) {
+	t.Helper()
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(attempts.Add(1))
+		reply := replies[min(n, len(replies))-1]
+		w.Header().Set("Content-Type", "application/json")
+		if reply.retryAfter != "" {
+			w.Header().Set("Retry-After", reply.retryAfter)
+		}
+		w.WriteHeader(reply.status)
+		if reply.status == http.StatusOK {
+			json.NewEncoder(w).Encode(map[string]any{"object": "page", "id": "page-1"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"object": "error", "status": reply.status, "code": "test_error", "message": "failed"})
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewClient("secret",
+		WithBaseURL(srv.URL+"/v1/"),
+		WithRateLimit(0, 0),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Second}),
+		WithLogger(slog.New(slog.DiscardHandler)),
+	)
+	return client, &attempts
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		replies      []reply
+		post         bool
+		wantAttempts int32
+		wantStatus   int
+	}{
+		{
+			name:         "gateway error then success",
+			replies:      []reply{{status: 503}, {status: 502}, {status: 200}},
+			wantAttempts: 3,
+		},
+		{
+			name:         "rate limited then success",
+			replies:      []reply{{status: 429, retryAfter: "0"}, {status: 200}},
+			wantAttempts: 2,
+		},
+		{
+			name:         "gives up after MaxRetries",
+			replies:      []reply{{status: 504}},
+			wantAttempts: 3,
+			wantStatus:   504,
+		},
+		{
+			name:         "Retry-After longer than MaxDelay",
+			replies:      []reply{{status: 429, retryAfter: "60"}, {status: 200}},
+			wantAttempts: 1,
+			wantStatus:   429,
+		},
+		{
+			name:         "client error",
+			replies:      []reply{{status: 400}, {status: 200}},
+			wantAttempts: 1,
+			wantStatus:   400,
+		},
+		{
+			name:         "POST is not idempotent",
+			replies:      []reply{{status: 503}, {status: 200}},
+			post:         true,
+			wantAttempts: 1,
+			wantStatus:   503,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, attempts := newRetryServer(t, tt.replies)
+
+			var err error
+			if tt.post {
+				req, _ := http.NewRequest(http.MethodPost, client.buildURL("pages"), strings.NewReader("{}"))
+				err = client.do(req, &PageResponse{})
+			} else {
+				_, err = Page("page-1").Fetch(client)
+			}
+
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			apiErr, ok := asAPIError(err)
+			if !ok {
+				t.Fatalf("error = %v, want *APIError", err)
+			}
+			if apiErr.Status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", apiErr.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRetryAfterError(t *testing.T) {
+	client, _ := newRetryServer(t, []reply{{status: 429, retryAfter: "60"}})
+	_, err := Page("page-1").Fetch(client)
+	if !IsRateLimited(err) {
+		t.Fatalf("error = %v, want rate limited", err)
+	}
+	apiErr, _ := asAPIError(err)
+	if apiErr.RetryAfter != time.Minute {
+		t.Errorf("RetryAfter = %v, want 1m", apiErr.RetryAfter)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{header: "", want: 0},
+		{header: "0", want: 0},
+		{header: "5", want: 5 * time.Second},
+		{header: "soon", want: 0},
+		{header: "Mon, 02 Jan 2006 15:04:05 GMT", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got := parseRetryAfter(tt.header)
+			// a date in the past comes out negative, which backoff ignores
+			if tt.want == 0 && got > 0 || tt.want != 0 && got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+
+	future := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(future); got < 59*time.Minute || got > time.Hour {
+		t.Errorf("parseRetryAfter(%q) = %v, want about 1h", future, got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter time.Duration
+		wantMin    time.Duration
+		wantMax    time.Duration
+		wantOK     bool
+	}{
+		{name: "first retry", attempt: 0, wantMin: 50 * time.Millisecond, wantMax: 100 * time.Millisecond, wantOK: true},
+		{name: "doubles", attempt: 2, wantMin: 200 * time.Millisecond, wantMax: 400 * time.Millisecond, wantOK: true},
+		{name: "capped", attempt: 10, wantMin: 500 * time.Millisecond, wantMax: time.Second, wantOK: true},
+		{name: "Retry-After", attempt: 0, retryAfter: 800 * time.Millisecond, wantMin: 800 * time.Millisecond, wantMax: 800 * time.Millisecond, wantOK: true},
+		{name: "Retry-After too long", attempt: 0, retryAfter: 2 * time.Second, wantMin: 2 * time.Second, wantMax: 2 * time.Second, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, ok := policy.backoff(tt.attempt, tt.retryAfter)
+			if ok != tt.wantOK {
+				t.Errorf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if delay < tt.wantMin || delay > tt.wantMax {
+				t.Errorf("delay = %v, want between %v and %v", delay, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
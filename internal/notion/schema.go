@@ -0,0 +1,114 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// PropertySchema describes a data source column. Type says which of the
+// type-specific fields is set; types without configuration have none.
+type PropertySchema struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Description string          `json:"description,omitempty"`
+	Formula     *FormulaSchema  `json:"formula,omitempty"`
+	MultiSelect *OptionsSchema  `json:"multi_select,omitempty"`
+	Number      *NumberSchema   `json:"number,omitempty"`
+	Relation    *RelationSchema `json:"relation,omitempty"`
+	Rollup      *RollupSchema   `json:"rollup,omitempty"`
+	Select      *OptionsSchema  `json:"select,omitempty"`
+	Status      *StatusSchema   `json:"status,omitempty"`
+	UniqueID    *UniqueIDSchema `json:"unique_id,omitempty"`
+}
+
+type FormulaSchema struct {
+	Expression string `json:"expression"`
+}
+
+// NumberSchema holds how Notion displays the number, e.g. "number",
+// "percent" or "dollar".
+type NumberSchema struct {
+	Format string `json:"format"`
+}
+
+type OptionsSchema struct {
+	Options []Select `json:"options"`
+}
+
+// StatusSchema lists the status options and the groups ("To-do",
+// "In progress", "Complete") they belong to.
+type StatusSchema struct {
+	Options []Select      `json:"options"`
+	Groups  []StatusGroup `json:"groups"`
+}
+
+type StatusGroup struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Color     string   `json:"color"`
+	OptionIDs []string `json:"option_ids"`
+}
+
+// RelationSchema points at the related data source. Type is
+// "single_property", or "dual_property" when the relation is mirrored by a
+// property on the other side.
+type RelationSchema struct {
+	DatasourceID string              `json:"data_source_id"`
+	DatabaseID   string              `json:"database_id,omitempty"`
+	Type         string              `json:"type"`
+	DualProperty *DualPropertySchema `json:"dual_property,omitempty"`
+}
+
+type DualPropertySchema struct {
+	SyncedPropertyName string `json:"synced_property_name"`
+	SyncedPropertyID   string `json:"synced_property_id"`
+}
+
+type RollupSchema struct {
+	Function             string `json:"function"`
+	RelationPropertyName string `json:"relation_property_name"`
+	RelationPropertyID   string `json:"relation_property_id"`
+	RollupPropertyName   string `json:"rollup_property_name"`
+	RollupPropertyID     string `json:"rollup_property_id"`
+}
+
+type UniqueIDSchema struct {
+	Prefix *string `json:"prefix"`
+}
+
+// Options returns the choices of a select, multi_select or status property,
+// with their colors, and nil for any other type.
+func (ps PropertySchema) Options() []Select {
+	switch {
+	case ps.Select != nil:
+		return ps.Select.Options
+	case ps.MultiSelect != nil:
+		return ps.MultiSelect.Options
+	case ps.Status != nil:
+		return ps.Status.Options
+	default:
+		return nil
+	}
+}
+
+// Validate checks that the data source has every property in expected, a map
+// of property name to type. An empty type accepts any type. All problems are
+// reported together.
+func (d *DatasourceGetResponse) Validate(expected map[string]string) error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(expected)) {
+		propType := expected[name]
+		prop, ok := d.Properties[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("notion: data source %s has no property %q", d.ID, name))
+			continue
+		}
+		if propType != "" && prop.Type != propType {
+			errs = append(errs, fmt.Errorf("notion: property %q of data source %s is %s, not %s", name, d.ID, prop.Type, propType))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,183 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// SearchRequest finds pages and data sources shared with the integration
+// whose titles match a query.
+type SearchRequest struct {
+	query  string
+	object string
+	sort   *Sort
+	cursor
+}
+
+type SearchResponse struct {
+	Object  string         `json:"object"`
+	Results []SearchResult `json:"results"`
+	Pagination
+}
+
+// SearchResult is either a page or a data source; Object says which field is
+// set.
+type SearchResult struct {
+	Object     string
+	Page       *PageResponse
+	Datasource *DatasourceGetResponse
+}
+
+// Search matches query against titles. An empty query returns everything
+// shared with the integration.
+func Search(query string) *SearchRequest {
+	return &SearchRequest{query: query}
+}
+
+// Pages limits results to pages.
+func (sr *SearchRequest) Pages() *SearchRequest {
+	sr.object = "page"
+	return sr
+}
+
+// Datasources limits results to data sources.
+func (sr *SearchRequest) Datasources() *SearchRequest {
+	sr.object = "data_source"
+	return sr
+}
+
+// Sort orders results; Notion only supports SortByTimestamp(LastEditedTime).
+// Without it results are ordered by relevance.
+func (sr *SearchRequest) Sort(sort Sort) *SearchRequest {
+	sr.sort = &sort
+	return sr
+}
+
+func (sr *SearchRequest) StartCursor(startCursor string) *SearchRequest {
+	sr.startCursor = startCursor
+	return sr
+}
+
+// PageSize sets the number of results per page (Notion allows at most 100).
+func (sr *SearchRequest) PageSize(pageSize int) *SearchRequest {
+	sr.pageSize = pageSize
+	return sr
+}
+
+// All makes Fetch follow next_cursor until every result has been read.
+func (sr *SearchRequest) All() *SearchRequest {
+	sr.all = true
+	return sr
+}
+
+func (sr *SearchRequest) Fetch(c *Client) (*SearchResponse, error) {
+	return sr.FetchContext(context.Background(), c)
+}
+
+func (sr *SearchRequest) FetchContext(ctx context.Context, c *Client) (*SearchResponse, error) {
+	searchResp, err := sr.fetchPage(ctx, c, sr.startCursor)
+	if err != nil {
+		return nil, err
+	}
+	if !sr.all {
+		return searchResp, nil
+	}
+
+	for {
+		next, ok := searchResp.Pagination.next()
+		if !ok {
+			return searchResp, nil
+		}
+		page, err := sr.fetchPage(ctx, c, next)
+		if err != nil {
+			return nil, err
+		}
+		searchResp.Results = append(searchResp.Results, page.Results...)
+		searchResp.Pagination = page.Pagination
+	}
+}
+
+func (sr *SearchRequest) fetchPage(ctx context.Context, c *Client, startCursor string) (*SearchResponse, error) {
+	payload := map[string]any{}
+	if sr.query != "" {
+		payload["query"] = sr.query
+	}
+	if sr.object != "" {
+		payload["filter"] = map[string]any{
+			"property": "object",
+			"value":    sr.object,
+		}
+	}
+	if sr.sort != nil {
+		payload["sort"] = sr.sort
+	}
+	if startCursor != "" {
+		payload["start_cursor"] = startCursor
+	}
+	if sr.pageSize > 0 {
+		payload["page_size"] = sr.pageSize
+	}
+	jsonBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	url := c.buildURL("search")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	var searchResp SearchResponse
+	err = c.do(req, &searchResp)
+	if err != nil {
+		return nil, err
+	}
+	return &searchResp, nil
+}
+
+func (r *SearchResult) UnmarshalJSON(data []byte) error {
+	var head struct {
+		Object string `json:"object"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return err
+	}
+
+	r.Object = head.Object
+	switch head.Object {
+	case "page":
+		r.Page = &PageResponse{}
+		return json.Unmarshal(data, r.Page)
+	case "data_source":
+		r.Datasource = &DatasourceGetResponse{}
+		return json.Unmarshal(data, r.Datasource)
+	default:
+		return nil
+	}
+}
+
+// PageByTitle returns the first page in the results whose title matches,
+// ignoring case.
+func (sr *SearchResponse) PageByTitle(title string) (*PageResponse, bool) {
+	for _, result := range sr.Results {
+		if result.Page != nil && strings.EqualFold(result.Page.Title(), title) {
+			return result.Page, true
+		}
+	}
+	return nil, false
+}
+
+// DatasourceByTitle returns the first data source in the results whose title
+// matches, ignoring case.
+func (sr *SearchResponse) DatasourceByTitle(title string) (*DatasourceGetResponse, bool) {
+	for _, result := range sr.Results {
+		if result.Datasource != nil && strings.EqualFold(PlainText(result.Datasource.Title), title) {
+			return result.Datasource, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,54 @@
+package notion
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+type Direction string
+
+const (
+	Ascending  Direction = "ascending"
+	Descending Direction = "descending"
+)
+
+// Sort orders query results by a property or a timestamp. Pass several to
+// Query to break ties; earlier sorts take precedence.
+type Sort struct {
+	property  string
+	timestamp TimestampKind
+	direction Direction
+}
+
+// SortBy sorts ascending on the property with the given name or ID.
+func SortBy(property string) Sort {
+	return Sort{property: property, direction: Ascending}
+}
+
+// SortByTimestamp sorts ascending on when pages were created or last edited.
+func SortByTimestamp(kind TimestampKind) Sort {
+	return Sort{timestamp: kind, direction: Ascending}
+}
+
+func (s Sort) Asc() Sort {
+	s.direction = Ascending
+	return s
+}
+
+func (s Sort) Desc() Sort {
+	s.direction = Descending
+	return s
+}
+
+func (s Sort) MarshalJSON() ([]byte, error) {
+	body := map[string]any{"direction": s.direction}
+	switch {
+	case s.timestamp != "":
+		body["timestamp"] = s.timestamp
+	case s.property != "":
+		body["property"] = s.property
+	default:
+		return nil, errors.New("notion: sort needs a property or timestamp")
+	}
+	return json.Marshal(body)
+}
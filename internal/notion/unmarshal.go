@@ -0,0 +1,323 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Unmarshal copies the properties of page into the struct pointed to by v.
+// Fields are matched by a `notion:"Property Name"` tag and untagged fields
+// are left alone. Options follow the name after commas:
+//
+//	required  a missing or empty property is an error
+//	end       read the end of a date range instead of its start
+//
+// Properties convert as follows:
+//
+//	string     title, rich_text, select, status, url, email, phone_number,
+//	           unique_id, the start of a date, the first URL of files
+//	[]string   multi_select names, files URLs, relation IDs, people names
+//	time.Time  date, created_time, last_edited_time
+//	bool       checkbox
+//	numbers    number, the number of a unique_id
+//
+// Formula and rollup values convert as their result type. A pointer field is
+// left nil when the property is empty. All problems are reported together.
+func Unmarshal(page *PageResponse, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("notion: Unmarshal needs a pointer to a struct, not %T", v)
+	}
+	rv = rv.Elem()
+
+	var errs []error
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		tag, ok := field.Tag.Lookup("notion")
+		if !ok || tag == "-" || !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		required := hasOption(opts, "required")
+
+		prop, ok := page.Properties[name]
+		if !ok {
+			if required {
+				errs = append(errs, fmt.Errorf("notion: page %s has no property %q", page.ID, name))
+			}
+			continue
+		}
+
+		set, err := decodeProperty(prop, hasOption(opts, "end"), rv.Field(i))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notion: property %q into field %s: %w", name, field.Name, err))
+			continue
+		}
+		if !set && required {
+			errs = append(errs, fmt.Errorf("notion: required property %q of page %s is empty", name, page.ID))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func hasOption(opts string, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if strings.TrimSpace(opt) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeProperty stores prop in dst, reporting whether it had a value.
+func decodeProperty(prop Property, end bool, dst reflect.Value) (bool, error) {
+	if dst.Kind() == reflect.Pointer {
+		elem := reflect.New(dst.Type().Elem())
+		set, err := decodeProperty(prop, end, elem.Elem())
+		if set && err == nil {
+			dst.Set(elem)
+		}
+		return set, err
+	}
+
+	// formulas and number or date rollups decode as their result
+	prop = propertyResult(prop)
+	// the end of a date range decodes as if it were the start
+	if end && prop.Date != nil {
+		rangeEnd := Date{TimeZone: prop.Date.TimeZone}
+		if prop.Date.End != nil {
+			rangeEnd.Start = *prop.Date.End
+		}
+		prop.Date = &rangeEnd
+	}
+
+	switch {
+	case dst.Type() == timeType:
+		t, set, err := propertyTime(prop)
+		if set {
+			dst.Set(reflect.ValueOf(t))
+		}
+		return set, err
+	case dst.Kind() == reflect.String:
+		s, set, err := propertyString(prop)
+		if set {
+			dst.SetString(s)
+		}
+		return set, err
+	case dst.Kind() == reflect.Bool:
+		b, set, err := propertyBool(prop)
+		if set {
+			dst.SetBool(b)
+		}
+		return set, err
+	case dst.CanInt(), dst.CanUint(), dst.CanFloat():
+		n, set, err := propertyNumber(prop)
+		if set {
+			err = setNumber(dst, n)
+		}
+		return set, err
+	case dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.String:
+		list, set, err := propertyStrings(prop)
+		if set {
+			slice := reflect.MakeSlice(dst.Type(), len(list), len(list))
+			for i, s := range list {
+				slice.Index(i).SetString(s)
+			}
+			dst.Set(slice)
+		}
+		return set, err
+	}
+	return false, fmt.Errorf("unsupported field type %s", dst.Type())
+}
+
+// propertyResult turns formula and rollup properties into a property of the
+// result's type.
+func propertyResult(prop Property) Property {
+	switch {
+	case prop.Type == "formula" && prop.Formula != nil:
+		f := prop.Formula
+		result := Property{ID: prop.ID, Type: f.Type, Date: f.Date, Number: f.Number}
+		switch f.Type {
+		case "boolean":
+			result.Type = "checkbox"
+			result.Checkbox = f.Bool != nil && *f.Bool
+		case "string":
+			result.Type = "rich_text"
+			if f.String != nil {
+				result.RichText = []*RichText{{Type: "text", PlainText: *f.String}}
+			}
+		}
+		return result
+	case prop.Type == "rollup" && prop.Rollup != nil && prop.Rollup.Type != "array":
+		return Property{ID: prop.ID, Type: prop.Rollup.Type, Date: prop.Rollup.Date, Number: prop.Rollup.Number}
+	}
+	return prop
+}
+
+func mismatch(prop Property, want string) error {
+	return fmt.Errorf("cannot convert %s to %s", prop.Type, want)
+}
+
+func joinText(richText []*RichText) string {
+	var text strings.Builder
+	for _, rt := range richText {
+		text.WriteString(rt.PlainText)
+	}
+	return text.String()
+}
+
+func propertyString(prop Property) (string, bool, error) {
+	var s *string
+	switch prop.Type {
+	case "title":
+		text := joinText(prop.Title)
+		return text, text != "", nil
+	case "rich_text":
+		text := joinText(prop.RichText)
+		return text, text != "", nil
+	case "select":
+		if prop.Select != nil {
+			s = &prop.Select.Name
+		}
+	case "status":
+		if prop.Status != nil {
+			s = &prop.Status.Name
+		}
+	case "url":
+		s = prop.Url
+	case "email":
+		s = prop.Email
+	case "phone_number":
+		s = prop.PhoneNumber
+	case "date":
+		if prop.Date != nil {
+			s = &prop.Date.Start
+		}
+	case "files":
+		if len(prop.Files) > 0 {
+			return prop.Files[0].GetURL(), true, nil
+		}
+	case "unique_id":
+		if prop.UniqueID != nil && prop.UniqueID.Number != nil {
+			id := strconv.Itoa(*prop.UniqueID.Number)
+			if prop.UniqueID.Prefix != nil {
+				id = *prop.UniqueID.Prefix + "-" + id
+			}
+			return id, true, nil
+		}
+	default:
+		return "", false, mismatch(prop, "string")
+	}
+	if s == nil || *s == "" {
+		return "", false, nil
+	}
+	return *s, true, nil
+}
+
+func propertyStrings(prop Property) ([]string, bool, error) {
+	var list []string
+	switch prop.Type {
+	case "multi_select":
+		for _, opt := range prop.MultiSelect {
+			list = append(list, opt.Name)
+		}
+	case "files":
+		for _, file := range prop.Files {
+			list = append(list, file.GetURL())
+		}
+	case "relation":
+		for _, rel := range prop.Relation {
+			list = append(list, rel.ID)
+		}
+	case "people":
+		for _, person := range prop.People {
+			list = append(list, person.Name)
+		}
+	default:
+		return nil, false, mismatch(prop, "[]string")
+	}
+	return list, len(list) > 0, nil
+}
+
+func propertyTime(prop Property) (time.Time, bool, error) {
+	switch prop.Type {
+	case "created_time":
+		if prop.CreatedTime != nil {
+			return *prop.CreatedTime, true, nil
+		}
+	case "last_edited_time":
+		if prop.LastEditedTime != nil {
+			return *prop.LastEditedTime, true, nil
+		}
+	case "date":
+		if prop.Date == nil || prop.Date.Start == "" {
+			break
+		}
+		t, err := parseDate(prop.Date.Start)
+		return t, err == nil, err
+	default:
+		return time.Time{}, false, mismatch(prop, "time.Time")
+	}
+	return time.Time{}, false, nil
+}
+
+// parseDate reads Notion's date-only and date-time formats.
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func propertyBool(prop Property) (bool, bool, error) {
+	if prop.Type != "checkbox" {
+		return false, false, mismatch(prop, "bool")
+	}
+	return prop.Checkbox, true, nil
+}
+
+func propertyNumber(prop Property) (float64, bool, error) {
+	switch prop.Type {
+	case "number":
+		if prop.Number != nil {
+			return *prop.Number, true, nil
+		}
+	case "unique_id":
+		if prop.UniqueID != nil && prop.UniqueID.Number != nil {
+			return float64(*prop.UniqueID.Number), true, nil
+		}
+	default:
+		return 0, false, mismatch(prop, "number")
+	}
+	return 0, false, nil
+}
+
+func setNumber(dst reflect.Value, n float64) error {
+	switch {
+	case dst.CanFloat():
+		if dst.OverflowFloat(n) {
+			return fmt.Errorf("%v overflows %s", n, dst.Type())
+		}
+		dst.SetFloat(n)
+	case n != math.Trunc(n):
+		return fmt.Errorf("%v is not a whole number for %s", n, dst.Type())
+	case dst.CanInt():
+		if dst.OverflowInt(int64(n)) {
+			return fmt.Errorf("%v overflows %s", n, dst.Type())
+		}
+		dst.SetInt(int64(n))
+	default:
+		if n < 0 || dst.OverflowUint(uint64(n)) {
+			return fmt.Errorf("%v overflows %s", n, dst.Type())
+		}
+		dst.SetUint(uint64(n))
+	}
+	return nil
+}
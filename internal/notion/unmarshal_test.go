@@ -0,0 +1,142 @@
+package notion_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/danecwalker/portfolio/internal/notion"
+)
+
+type unmarshalRow struct {
+	Name    string    `notion:"Name,required"`
+	Notes   string    `notion:"Notes"`
+	Kind    string    `notion:"Kind"`
+	Tags    []string  `notion:"Tags"`
+	Stars   int       `notion:"Stars"`
+	Score   float64   `notion:"Score"`
+	Public  bool      `notion:"Public"`
+	Start   time.Time `notion:"Date"`
+	End     *string   `notion:"Date,end"`
+	Link    *string   `notion:"Link"`
+	Summary string    `notion:"Summary"`
+	Ignored string
+}
+
+// withPlainText fills in the plain_text Notion returns alongside the text
+// the *Value constructors set.
+func withPlainText(props map[string]notion.Property) map[string]notion.Property {
+	for _, prop := range props {
+		for _, rt := range append(prop.Title, prop.RichText...) {
+			rt.PlainText = rt.Text.Content
+		}
+	}
+	return props
+}
+
+func TestUnmarshal(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	summary := "shipped"
+	endText := "2024-06-30"
+	link := "https://example.com"
+
+	tests := []struct {
+		name    string
+		props   map[string]notion.Property
+		want    unmarshalRow
+		wantErr bool
+	}{
+		{
+			name: "every type",
+			props: map[string]notion.Property{
+				"Name":    notion.TitleValue("Portfolio"),
+				"Notes":   notion.RichTextValue("built with Go"),
+				"Kind":    notion.SelectValue("site"),
+				"Tags":    notion.MultiSelectValue("go", "svelte"),
+				"Stars":   notion.NumberValue(42),
+				"Score":   notion.NumberValue(4.5),
+				"Public":  notion.CheckboxValue(true),
+				"Date":    notion.DateRangeValue(start, end),
+				"Link":    notion.URLValue(link),
+				"Summary": {Type: "formula", Formula: &notion.Formula{Type: "string", String: &summary}},
+			},
+			want: unmarshalRow{
+				Name:    "Portfolio",
+				Notes:   "built with Go",
+				Kind:    "site",
+				Tags:    []string{"go", "svelte"},
+				Stars:   42,
+				Score:   4.5,
+				Public:  true,
+				Start:   start,
+				End:     &endText,
+				Link:    &link,
+				Summary: "shipped",
+			},
+		},
+		{
+			name: "empty optional values",
+			props: map[string]notion.Property{
+				"Name": notion.TitleValue("Portfolio"),
+				"Date": notion.DateValue(start),
+			},
+			want: unmarshalRow{Name: "Portfolio", Start: start},
+		},
+		{
+			name:    "missing required property",
+			props:   map[string]notion.Property{"Notes": notion.RichTextValue("no title")},
+			wantErr: true,
+		},
+		{
+			name:    "empty required property",
+			props:   map[string]notion.Property{"Name": notion.TitleValue("")},
+			wantErr: true,
+		},
+		{
+			name: "fraction into an int",
+			props: map[string]notion.Property{
+				"Name":  notion.TitleValue("Portfolio"),
+				"Stars": notion.NumberValue(1.5),
+			},
+			wantErr: true,
+		},
+		{
+			name: "mismatched type",
+			props: map[string]notion.Property{
+				"Name":   notion.TitleValue("Portfolio"),
+				"Public": notion.RichTextValue("yes"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := &notion.PageResponse{Properties: withPlainText(tt.props)}
+
+			var got unmarshalRow
+			err := notion.Unmarshal(page, &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalNeedsStructPointer(t *testing.T) {
+	page := &notion.PageResponse{}
+	for _, v := range []any{nil, unmarshalRow{}, new(string), (*unmarshalRow)(nil)} {
+		if err := notion.Unmarshal(page, v); err == nil {
+			t.Errorf("Unmarshal(%T) succeeded", v)
+		}
+	}
+}
@@ -1,6 +1,158 @@
 package notion
 
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// User is a person or bot. Users referenced from pages and blocks are often
+// partial, with only Object and ID set; UserResolver fills in the rest.
 type User struct {
+	Object    string  `json:"object"`
+	ID        string  `json:"id"`
+	Type      string  `json:"type,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	AvatarURL *string `json:"avatar_url,omitempty"`
+	Person    *Person `json:"person,omitempty"`
+	Bot       *Bot    `json:"bot,omitempty"`
+}
+
+// Person is only returned to integrations with the user email capability.
+type Person struct {
+	Email string `json:"email,omitempty"`
+}
+
+type Bot struct {
+	Owner         *BotOwner `json:"owner,omitempty"`
+	WorkspaceName string    `json:"workspace_name,omitempty"`
+}
+
+type BotOwner struct {
+	Type      string `json:"type"`
+	Workspace bool   `json:"workspace,omitempty"`
+	User      *User  `json:"user,omitempty"`
+}
+
+// Email returns the person's email, or "" for bots and when the integration
+// can't read emails.
+func (u User) Email() string {
+	if u.Person == nil {
+		return ""
+	}
+	return u.Person.Email
+}
+
+type UserRequest struct {
+	method string
+	userId string
+	cursor
+}
+
+type UserResponse struct {
 	Object string `json:"object"`
-	ID     string `json:"id"`
+	User
+	UserListResponse
+}
+
+type UserListResponse struct {
+	Results []User `json:"results"`
+	Pagination
+}
+
+// Users lists every user in the workspace, guests excluded.
+func Users() *UserRequest {
+	return &UserRequest{
+		method: "LIST",
+	}
+}
+
+func UserByID(userId string) *UserRequest {
+	return &UserRequest{
+		method: "GET",
+		userId: userId,
+	}
+}
+
+// Me returns the integration's bot user.
+func Me() *UserRequest {
+	return &UserRequest{
+		method: "GET",
+		userId: "me",
+	}
+}
+
+func (ur *UserRequest) StartCursor(startCursor string) *UserRequest {
+	ur.startCursor = startCursor
+	return ur
+}
+
+// PageSize sets the number of users per page (Notion allows at most 100).
+func (ur *UserRequest) PageSize(pageSize int) *UserRequest {
+	ur.pageSize = pageSize
+	return ur
+}
+
+// All makes Fetch follow next_cursor until every user has been listed.
+func (ur *UserRequest) All() *UserRequest {
+	ur.all = true
+	return ur
+}
+
+func (ur *UserRequest) Fetch(c *Client) (*UserResponse, error) {
+	return ur.FetchContext(context.Background(), c)
+}
+
+func (ur *UserRequest) FetchContext(ctx context.Context, c *Client) (*UserResponse, error) {
+	userResp, err := ur.fetchPage(ctx, c, ur.startCursor)
+	if err != nil {
+		return nil, err
+	}
+	if ur.method != "LIST" || !ur.all {
+		return userResp, nil
+	}
+
+	for {
+		next, ok := userResp.Pagination.next()
+		if !ok {
+			return userResp, nil
+		}
+		page, err := ur.fetchPage(ctx, c, next)
+		if err != nil {
+			return nil, err
+		}
+		userResp.Results = append(userResp.Results, page.Results...)
+		userResp.Pagination = page.Pagination
+	}
+}
+
+func (ur *UserRequest) fetchPage(ctx context.Context, c *Client, startCursor string) (*UserResponse, error) {
+	endpoint := "users/" + ur.userId
+	query := url.Values{}
+	if ur.method == "LIST" {
+		endpoint = "users"
+		if startCursor != "" {
+			query.Set("start_cursor", startCursor)
+		}
+		if ur.pageSize > 0 {
+			query.Set("page_size", strconv.Itoa(ur.pageSize))
+		}
+	}
+
+	reqURL := c.buildURL(endpoint)
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var userResp UserResponse
+	err = c.do(req, &userResp)
+	if err != nil {
+		return nil, err
+	}
+	return &userResp, nil
 }
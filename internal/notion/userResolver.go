@@ -0,0 +1,142 @@
+package notion
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// UserResolver fills in the name, avatar and email of the partial users that
+// pages, blocks and mentions reference. Lookups, failed ones included, are
+// cached for ttl, and concurrent lookups of the same user share one request,
+// so a page with many mentions of the same person costs one request.
+type UserResolver struct {
+	client *Client
+	ttl    time.Duration
+	mu     sync.Mutex
+	users  map[string]cachedUser
+	calls  map[string]*userCall
+}
+
+// cachedUser is a looked up user, or the error the lookup failed with.
+type cachedUser struct {
+	user    User
+	err     error
+	fetched time.Time
+}
+
+// userCall is a lookup in flight. Resolve calls for the same user wait on it
+// instead of sending their own request.
+type userCall struct {
+	done chan struct{}
+	user User
+	err  error
+}
+
+func NewUserResolver(c *Client, ttl time.Duration) *UserResolver {
+	return &UserResolver{
+		client: c,
+		ttl:    ttl,
+		users:  map[string]cachedUser{},
+		calls:  map[string]*userCall{},
+	}
+}
+
+// Resolve returns user with its details filled in. Users that already have
+// a name are returned as is.
+func (r *UserResolver) Resolve(ctx context.Context, user User) (User, error) {
+	if user.Name != "" || user.ID == "" {
+		return user, nil
+	}
+
+	r.mu.Lock()
+	if cached, ok := r.cached(user.ID); ok {
+		r.mu.Unlock()
+		if cached.err != nil {
+			return user, cached.err
+		}
+		return cached.user, nil
+	}
+	call, inFlight := r.calls[user.ID]
+	if !inFlight {
+		call = &userCall{done: make(chan struct{})}
+		r.calls[user.ID] = call
+	}
+	r.mu.Unlock()
+
+	if !inFlight {
+		r.fetch(ctx, user.ID, call)
+	}
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return user, ctx.Err()
+	}
+	if call.err != nil {
+		return user, call.err
+	}
+	return call.user, nil
+}
+
+// fetch looks the user up for everyone waiting on call and caches the
+// result.
+func (r *UserResolver) fetch(ctx context.Context, userId string, call *userCall) {
+	userResp, err := UserByID(userId).FetchContext(ctx, r.client)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		call.err = err
+		// a caller giving up says nothing about the user, so only Notion's
+		// answer is cached
+		if ctx.Err() == nil {
+			r.users[userId] = cachedUser{err: err, fetched: time.Now()}
+		}
+	} else {
+		call.user = userResp.User
+		r.users[userId] = cachedUser{user: call.user, fetched: time.Now()}
+	}
+	delete(r.calls, userId)
+	close(call.done)
+}
+
+// ResolveAll resolves every user, stopping at the first error.
+func (r *UserResolver) ResolveAll(ctx context.Context, users []User) ([]User, error) {
+	resolved := make([]User, len(users))
+	for i, user := range users {
+		u, err := r.Resolve(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		resolved[i] = u
+	}
+	return resolved, nil
+}
+
+// Preload caches every workspace member with one paginated list request,
+// which is cheaper than resolving them one at a time.
+func (r *UserResolver) Preload(ctx context.Context) error {
+	userResp, err := Users().All().FetchContext(ctx, r.client)
+	if err != nil {
+		return err
+	}
+	for _, user := range userResp.Results {
+		r.store(user)
+	}
+	return nil
+}
+
+// cached returns the unexpired lookup of userId. r.mu must be held.
+func (r *UserResolver) cached(userId string) (cachedUser, bool) {
+	cached, ok := r.users[userId]
+	if !ok || (r.ttl > 0 && time.Since(cached.fetched) > r.ttl) {
+		return cachedUser{}, false
+	}
+	return cached, true
+}
+
+func (r *UserResolver) store(user User) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users[user.ID] = cachedUser{user: user, fetched: time.Now()}
+}
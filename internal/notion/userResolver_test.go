@@ -0,0 +1,98 @@
+package notion_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/danecwalker/portfolio/internal/notion"
+)
+
+// countUserLookups returns a transport that counts user lookups, holding
+// each one until release is closed.
+func countUserLookups(lookups *atomic.Int32, release <-chan struct{}) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if strings.HasPrefix(req.URL.Path, "/v1/users/") {
+			lookups.Add(1)
+			<-release
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	}
+}
+
+func TestUserResolverCachesFailures(t *testing.T) {
+	var lookups atomic.Int32
+	release := make(chan struct{})
+	close(release)
+	srv, client := newFake(t, notion.WithTransport(countUserLookups(&lookups, release)))
+	srv.AddUsers(notion.User{ID: "ada", Name: "Ada"})
+
+	users := notion.NewUserResolver(client, time.Hour)
+	for range 3 {
+		if user, err := users.Resolve(context.Background(), notion.User{ID: "ada"}); err != nil || user.Name != "Ada" {
+			t.Fatalf("Resolve(ada) = %+v, %v", user, err)
+		}
+		if _, err := users.Resolve(context.Background(), notion.User{ID: "gone"}); !notion.IsNotFound(err) {
+			t.Fatalf("Resolve(gone) error = %v, want not found", err)
+		}
+	}
+	if got := lookups.Load(); got != 2 {
+		t.Errorf("sent %d lookups, want one per user", got)
+	}
+}
+
+func TestUserResolverSharesLookups(t *testing.T) {
+	var lookups atomic.Int32
+	release := make(chan struct{})
+	srv, client := newFake(t, notion.WithTransport(countUserLookups(&lookups, release)))
+	srv.AddUsers(notion.User{ID: "ada", Name: "Ada"})
+
+	users := notion.NewUserResolver(client, time.Hour)
+	var wg sync.WaitGroup
+	names := make([]string, 5)
+	for i := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			user, err := users.Resolve(context.Background(), notion.User{ID: "ada"})
+			if err != nil {
+				t.Errorf("Resolve: %v", err)
+			}
+			names[i] = user.Name
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := lookups.Load(); got != 1 {
+		t.Errorf("sent %d lookups, want 1", got)
+	}
+	for i, name := range names {
+		if name != "Ada" {
+			t.Errorf("caller %d got %q, want Ada", i, name)
+		}
+	}
+}
+
+func TestUserResolverCancelledLookupIsNotCached(t *testing.T) {
+	var lookups atomic.Int32
+	release := make(chan struct{})
+	close(release)
+	srv, client := newFake(t, notion.WithTransport(countUserLookups(&lookups, release)))
+	srv.AddUsers(notion.User{ID: "ada", Name: "Ada"})
+
+	users := notion.NewUserResolver(client, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := users.Resolve(ctx, notion.User{ID: "ada"}); err == nil {
+		t.Fatal("Resolve with a cancelled context succeeded")
+	}
+	if user, err := users.Resolve(context.Background(), notion.User{ID: "ada"}); err != nil || user.Name != "Ada" {
+		t.Errorf("Resolve after cancel = %+v, %v", user, err)
+	}
+}
@@ -0,0 +1,227 @@
+package notion
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventType is the type of a webhook event, e.g. "page.content_updated".
+type EventType string
+
+const (
+	EventPageCreated           EventType = "page.created"
+	EventPageContentUpdated    EventType = "page.content_updated"
+	EventPagePropertiesUpdated EventType = "page.properties_updated"
+	EventPageMoved             EventType = "page.moved"
+	EventPageDeleted           EventType = "page.deleted"
+	EventPageUndeleted         EventType = "page.undeleted"
+	EventPageLocked            EventType = "page.locked"
+	EventPageUnlocked          EventType = "page.unlocked"
+
+	EventDatabaseCreated        EventType = "database.created"
+	EventDatabaseContentUpdated EventType = "database.content_updated"
+	EventDatabaseMoved          EventType = "database.moved"
+	EventDatabaseDeleted        EventType = "database.deleted"
+	EventDatabaseUndeleted      EventType = "database.undeleted"
+	EventDatabaseSchemaUpdated  EventType = "database.schema_updated"
+
+	EventDataSourceCreated        EventType = "data_source.created"
+	EventDataSourceContentUpdated EventType = "data_source.content_updated"
+	EventDataSourceMoved          EventType = "data_source.moved"
+	EventDataSourceDeleted        EventType = "data_source.deleted"
+	EventDataSourceUndeleted      EventType = "data_source.undeleted"
+	EventDataSourceSchemaUpdated  EventType = "data_source.schema_updated"
+
+	EventCommentCreated EventType = "comment.created"
+	EventCommentUpdated EventType = "comment.updated"
+	EventCommentDeleted EventType = "comment.deleted"
+
+	// AnyEvent registers a callback for every event type.
+	AnyEvent EventType = "*"
+)
+
+// maxWebhookBody bounds the size of a webhook request body.
+const maxWebhookBody = 1 << 20
+
+// WebhookEvent is a change Notion reports to a webhook subscription. Events
+// only say what changed; fetch the entity for its current state.
+type WebhookEvent struct {
+	ID             string        `json:"id"`
+	Timestamp      time.Time     `json:"timestamp"`
+	WorkspaceID    string        `json:"workspace_id"`
+	WorkspaceName  string        `json:"workspace_name"`
+	SubscriptionID string        `json:"subscription_id"`
+	IntegrationID  string        `json:"integration_id"`
+	Type           EventType     `json:"type"`
+	Authors        []EventEntity `json:"authors"`
+	AttemptNumber  int           `json:"attempt_number"`
+	Entity         EventEntity   `json:"entity"`
+	Data           EventData     `json:"data"`
+}
+
+// EventEntity identifies a page, data source, block, comment or user.
+type EventEntity struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// EventData holds the type-specific details of an event. Fields not sent
+// for the event type are left empty.
+type EventData struct {
+	Parent            *EventEntity  `json:"parent,omitempty"`
+	UpdatedBlocks     []EventEntity `json:"updated_blocks,omitempty"`
+	UpdatedProperties []string      `json:"updated_properties,omitempty"`
+	// PageID is the page a comment event belongs to.
+	PageID string `json:"page_id,omitempty"`
+}
+
+// WebhookFunc handles an event. Returning an error answers Notion with a
+// 500 so it retries the delivery later.
+type WebhookFunc func(ctx context.Context, event *WebhookEvent) error
+
+// Webhook is an http.Handler receiving Notion integration webhooks. It
+// answers the verification handshake, checks each event's X-Notion-Signature
+// against the verification token and dispatches it to the callbacks
+// registered with On.
+type Webhook struct {
+	token string
+
+	mu             sync.RWMutex
+	handlers       []webhookHandler
+	onVerification func(r *http.Request, token string)
+}
+
+// webhookHandler is a callback registered with On.
+type webhookHandler struct {
+	eventType EventType
+	fn        WebhookFunc
+}
+
+// NewWebhook creates a handler for a subscription. token is the verification
+// token Notion sent when the subscription was created; until it is known,
+// pass "" and use OnVerification to receive it. Events are rejected without
+// a token.
+func NewWebhook(token string) *Webhook {
+	return &Webhook{
+		token: token,
+	}
+}
+
+// On registers fn for events of eventType, or for every event with
+// AnyEvent. Callbacks run in registration order before Notion is answered,
+// so they should be quick.
+func (wh *Webhook) On(eventType EventType, fn WebhookFunc) *Webhook {
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+	wh.handlers = append(wh.handlers, webhookHandler{eventType: eventType, fn: fn})
+	return wh
+}
+
+// OnVerification registers fn to receive the verification token Notion sends
+// when a subscription is created or the token is resent, with the request it
+// came in. The token must be pasted into the integration settings to finish
+// verification. Handshakes are unsigned, so anyone can send one.
+func (wh *Webhook) OnVerification(fn func(r *http.Request, token string)) *Webhook {
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+	wh.onVerification = fn
+	return wh
+}
+
+func (wh *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "could not read body", http.StatusBadRequest)
+		return
+	}
+
+	// the handshake is the only unsigned request Notion sends
+	if r.Header.Get("X-Notion-Signature") == "" {
+		var handshake struct {
+			VerificationToken string `json:"verification_token"`
+		}
+		if err := json.Unmarshal(body, &handshake); err != nil || handshake.VerificationToken == "" {
+			http.Error(w, "missing signature", http.StatusUnauthorized)
+			return
+		}
+
+		wh.mu.RLock()
+		onVerification := wh.onVerification
+		wh.mu.RUnlock()
+		if onVerification != nil {
+			onVerification(r, handshake.VerificationToken)
+		}
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !wh.validSignature(body, r.Header.Get("X-Notion-Signature")) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return
+	}
+
+	if err := wh.dispatch(r.Context(), &event); err != nil {
+		slog.ErrorContext(r.Context(), "notion webhook failed", "event_id", event.ID, "type", event.Type, "error", err)
+		http.Error(w, "event not handled", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// validSignature checks a "sha256=<hex>" HMAC of body keyed by the token.
+func (wh *Webhook) validSignature(body []byte, signature string) bool {
+	if wh.token == "" {
+		return false
+	}
+	digest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(digest)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(wh.token))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// dispatch runs the callbacks for event in registration order, stopping at
+// the first error.
+func (wh *Webhook) dispatch(ctx context.Context, event *WebhookEvent) error {
+	wh.mu.RLock()
+	handlers := wh.handlers
+	wh.mu.RUnlock()
+
+	for _, h := range handlers {
+		if h.eventType != event.Type && h.eventType != AnyEvent {
+			continue
+		}
+		if err := h.fn(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package notion_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/danecwalker/portfolio/internal/notion"
+)
+
+const webhookToken = "secret_webhook"
+
+func sign(token string, body string) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhook(t *testing.T) {
+	event := `{"id":"evt-1","type":"page.created","entity":{"id":"page-1","type":"page"}}`
+
+	tests := []struct {
+		name          string
+		token         string
+		method        string
+		body          string
+		signature     string
+		handlerErr    error
+		wantStatus    int
+		wantEvent     bool
+		wantHandshake string
+	}{
+		{
+			name:       "valid signature",
+			token:      webhookToken,
+			body:       event,
+			signature:  sign(webhookToken, event),
+			wantStatus: http.StatusOK,
+			wantEvent:  true,
+		},
+		{
+			name:       "signed with another token",
+			token:      webhookToken,
+			body:       event,
+			signature:  sign("other", event),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "signature of another body",
+			token:      webhookToken,
+			body:       event,
+			signature:  sign(webhookToken, event+" "),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "missing sha256 prefix",
+			token:      webhookToken,
+			body:       event,
+			signature:  strings.TrimPrefix(sign(webhookToken, event), "sha256="),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "signature not hex",
+			token:      webhookToken,
+			body:       event,
+			signature:  "sha256=not-hex",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "no token configured",
+			body:       event,
+			signature:  sign("", event),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "unsigned event",
+			token:      webhookToken,
+			body:       event,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "verification handshake",
+			body:          `{"verification_token":"secret_new"}`,
+			wantStatus:    http.StatusOK,
+			wantHandshake: "secret_new",
+		},
+		{
+			name:       "signed invalid JSON",
+			token:      webhookToken,
+			body:       "{",
+			signature:  sign(webhookToken, "{"),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "handler error",
+			token:      webhookToken,
+			body:       event,
+			signature:  sign(webhookToken, event),
+			handlerErr: errors.New("try again"),
+			wantStatus: http.StatusInternalServerError,
+			wantEvent:  true,
+		},
+		{
+			name:       "wrong method",
+			token:      webhookToken,
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *notion.WebhookEvent
+			var handshake string
+			wh := notion.NewWebhook(tt.token).
+				OnVerification(func(r *http.Request, token string) { handshake = token }).
+				On(notion.EventPageCreated, func(ctx context.Context, event *notion.WebhookEvent) error {
+					got = event
+					return tt.handlerErr
+				})
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "/webhook", strings.NewReader(tt.body))
+			if tt.signature != "" {
+				req.Header.Set("X-Notion-Signature", tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			wh.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if (got != nil) != tt.wantEvent {
+				t.Errorf("handler called = %v, want %v", got != nil, tt.wantEvent)
+			}
+			if got != nil && (got.ID != "evt-1" || got.Entity.ID != "page-1") {
+				t.Errorf("event = %+v", got)
+			}
+			if handshake != tt.wantHandshake {
+				t.Errorf("verification token = %q, want %q", handshake, tt.wantHandshake)
+			}
+		})
+	}
+}
+
+func TestWebhookDispatchOrder(t *testing.T) {
+	var calls []string
+	record := func(name string) notion.WebhookFunc {
+		return func(ctx context.Context, event *notion.WebhookEvent) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+	wh := notion.NewWebhook(webhookToken).
+		On(notion.AnyEvent, record("any")).
+		On(notion.EventPageDeleted, record("deleted")).
+		On(notion.EventPageCreated, record("created")).
+		On(notion.AnyEvent, record("any again"))
+
+	body := `{"id":"evt-1","type":"page.created"}`
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("X-Notion-Signature", sign(webhookToken, body))
+	wh.ServeHTTP(httptest.NewRecorder(), req)
+
+	if want := []string{"any", "created", "any again"}; !slices.Equal(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
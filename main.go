@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"log/slog"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/danecwalker/portfolio/frontend"
 	"github.com/danecwalker/portfolio/internal/notion"
@@ -15,6 +22,9 @@ import (
 func main() {
 	godotenv.Load()
 
+	logger := newLogger(os.Getenv("LOG_FORMAT"), os.Getenv("LOG_LEVEL"))
+	slog.SetDefault(logger)
+
 	notionKey := os.Getenv("NOTION_API_KEY")
 	if notionKey == "" {
 		panic("NOTION_API_KEY not set")
@@ -25,35 +35,101 @@ func main() {
 		panic("PROFILE_PAGE_ID not set")
 	}
 
-	linksDatasourceId := os.Getenv("LINKS_DATASOURCE_ID")
-	if linksDatasourceId == "" {
-		panic("LINKS_DATASOURCE_ID not set")
+	client := notion.NewClient(notionKey, notion.WithLogger(logger))
+
+	cfg := config{
+		profilePageId:            profilePageId,
+		linksDatasourceId:        datasourceId(client, "LINKS"),
+		experienceDatasourceId:   datasourceId(client, "EXPERIENCE"),
+		projectsDatasourceId:     datasourceId(client, "PROJECTS"),
+		affiliationsDatasourceId: datasourceId(client, "AFFILIATIONS"),
+		webhookToken:             os.Getenv("NOTION_WEBHOOK_TOKEN"),
+	}
+	// the verification token is the webhook's signing secret, so it is only
+	// logged when asked for
+	cfg.logWebhookToken, _ = strconv.ParseBool(os.Getenv("NOTION_WEBHOOK_LOG_TOKEN"))
+	for datasourceId, expected := range cfg.schemas() {
+		validateSchema(client, datasourceId, expected)
+	}
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	server := &http.Server{
+		Addr:    ":" + port,
+		Handler: CorsMiddleware(newMux(client, cfg)),
 	}
 
-	experienceDatasourceId := os.Getenv("EXPERIENCE_DATASOURCE_ID")
-	if experienceDatasourceId == "" {
-		panic("EXPERIENCE_DATASOURCE_ID not set")
+	logger.Info("starting server", "addr", server.Addr)
+	if err := server.ListenAndServe(); err != nil {
+		logger.Error("server stopped", "error", err)
+		os.Exit(1)
 	}
+}
+
+// config holds the Notion IDs and secrets the handlers need.
+type config struct {
+	profilePageId            string
+	linksDatasourceId        string
+	experienceDatasourceId   string
+	projectsDatasourceId     string
+	affiliationsDatasourceId string
+	webhookToken             string
+	logWebhookToken          bool
+}
 
-	projectsDatasourceId := os.Getenv("PROJECTS_DATASOURCE_ID")
-	if projectsDatasourceId == "" {
-		panic("PROJECTS_DATASOURCE_ID not set")
+// schemas maps each data source to the properties the handlers read.
+func (cfg config) schemas() map[string]map[string]string {
+	return map[string]map[string]string{
+		cfg.linksDatasourceId: {
+			"Name":          "title",
+			"Website URL":   "url",
+			"File":          "files",
+			"Hidden":        "checkbox",
+			"Display Order": "",
+		},
+		cfg.experienceDatasourceId: {
+			"Company":  "title",
+			"Position": "rich_text",
+			"Date":     "date",
+			"Hidden":   "checkbox",
+		},
+		cfg.projectsDatasourceId: {
+			"Name":        "title",
+			"Project URL": "url",
+			"Date":        "date",
+			"Hidden":      "checkbox",
+		},
+		cfg.affiliationsDatasourceId: {
+			"Name":          "title",
+			"Hidden":        "checkbox",
+			"Display Order": "",
+		},
 	}
+}
 
-	affiliationsDatasourceId := os.Getenv("AFFILIATIONS_DATASOURCE_ID")
-	if affiliationsDatasourceId == "" {
-		panic("AFFILIATIONS_DATASOURCE_ID not set")
+// newMux builds the API and frontend routes. Notion is only reached through
+// client, so the handlers can be served from a notiontest fake.
+func newMux(client *notion.Client, cfg config) *http.ServeMux {
+	datasources := map[string]string{
+		"links":        cfg.linksDatasourceId,
+		"experience":   cfg.experienceDatasourceId,
+		"projects":     cfg.projectsDatasourceId,
+		"affiliations": cfg.affiliationsDatasourceId,
 	}
 
-	client := notion.NewClient(notionKey)
+	// every datasource lets rows be hidden from the site with a checkbox
+	visible := notion.Prop("Hidden").Checkbox().Equals(false)
 
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/api/v1/profile", func(w http.ResponseWriter, r *http.Request) {
-		pageReq := notion.Page(profilePageId)
-		pageResp, err := pageReq.Fetch(client)
+		pageReq := notion.Page(cfg.profilePageId)
+		pageResp, err := pageReq.FetchContext(r.Context(), client)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
 
@@ -64,47 +140,36 @@ func main() {
 	})
 
 	mux.HandleFunc("/api/v1/links", func(w http.ResponseWriter, r *http.Request) {
-		linksReq := notion.Datasource(linksDatasourceId).Query(map[string]any{
-			"property": "Hidden",
-			"checkbox": map[string]any{
-				"equals": false,
-			},
-		}, []map[string]any{
-			{
-				"property":  "Display Order",
-				"direction": "ascending",
-			},
-		})
-		linksResp, err := linksReq.Fetch(client)
+		linksReq := notion.Datasource(cfg.linksDatasourceId).Query(visible, notion.SortBy("Display Order")).All()
+		linksResp, err := linksReq.FetchContext(r.Context(), client)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
 
 		var links []map[string]any
 		for _, row := range linksResp.Results {
-			title_row := row.Properties["Name"]
-			var title string
-			if len(title_row.Title) > 0 {
-				title = title_row.Title[0].PlainText
-			} else {
+			var link struct {
+				Title string `notion:"Name,required"`
+				URL   string `notion:"Website URL"`
+				File  string `notion:"File"`
+			}
+			if err := notion.Unmarshal(&row, &link); err != nil {
+				slog.Warn("skipping link", "page", row.ID, "err", err)
 				continue
 			}
-			url := row.Properties["Website URL"].Url
-			files := row.Properties["File"].Files
 
-			if len(files) > 0 {
-				file := files[0].GetURL()
+			if link.File != "" {
 				links = append(links, map[string]any{
-					"title": title,
+					"title": link.Title,
 					"type":  "file",
-					"url":   file,
+					"url":   link.File,
 				})
 			} else {
 				links = append(links, map[string]any{
-					"title": title,
+					"title": link.Title,
 					"type":  "link",
-					"url":   *url,
+					"url":   link.URL,
 				})
 			}
 		}
@@ -115,127 +180,168 @@ func main() {
 		})
 	})
 
+	mentionLinks := &pageLinks{client: client, profilePageId: cfg.profilePageId}
+	users := notion.NewUserResolver(client, time.Hour)
+	newRenderer := func(ctx context.Context) *renderer {
+		return &renderer{
+			pageURL: mentionLinks.resolver(ctx),
+			userName: func(user notion.User) string {
+				resolved, err := users.Resolve(ctx, user)
+				if err != nil {
+					return ""
+				}
+				return resolved.Name
+			},
+		}
+	}
+
 	mux.HandleFunc("/api/v1/content/{pageId}", func(w http.ResponseWriter, r *http.Request) {
 		pageId := r.PathValue("pageId")
-		blocksReq := notion.Blocks(pageId).Query()
-		blocksResp, err := blocksReq.Fetch(client)
+		blocksReq := notion.BlockTree(pageId)
+		blocksResp, err := blocksReq.FetchContext(r.Context(), client)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
 
-		var content strings.Builder
-		for _, row := range blocksResp.Results {
-			switch row.Type {
-			case "paragraph":
-				// check if we should close a bulleted list
-				if strings.HasSuffix(content.String(), "</li>\n") {
-					content.WriteString("</ul>\n")
-				}
-				var paragraphText []string
-				for _, rt := range row.Paragraph.RichText {
-					var p string = rt.PlainText
-					applyBold(&p, rt.Annotations.Bold)
-					applyItalic(&p, rt.Annotations.Italic)
-					applyStrikethrough(&p, rt.Annotations.Strikethrough)
-					applyUnderline(&p, rt.Annotations.Underline)
-					applyCode(&p, rt.Annotations.Code)
-					paragraphText = append(paragraphText, p)
-				}
-				content.WriteString("<p>" + strings.Join(paragraphText, " ") + "</p>\n")
-			case "heading_1":
-				if strings.HasSuffix(content.String(), "</li>\n") {
-					content.WriteString("</ul>\n")
-				}
-				var headingText []string
-				for _, rt := range row.Heading1.RichText {
-					headingText = append(headingText, rt.PlainText)
-				}
-				content.WriteString("<h1>" + strings.Join(headingText, " ") + "</h1>\n")
-			case "heading_2":
-				if strings.HasSuffix(content.String(), "</li>\n") {
-					content.WriteString("</ul>\n")
-				}
-				var headingText []string
-				for _, rt := range row.Heading2.RichText {
-					headingText = append(headingText, rt.PlainText)
-				}
-				content.WriteString("<h2>" + strings.Join(headingText, " ") + "</h2>\n")
-			case "heading_3":
-				if strings.HasSuffix(content.String(), "</li>\n") {
-					content.WriteString("</ul>\n")
-				}
-				var headingText []string
-				for _, rt := range row.Heading3.RichText {
-					headingText = append(headingText, rt.PlainText)
-				}
-				content.WriteString("<h3>" + strings.Join(headingText, " ") + "</h3>\n")
-			case "bulleted_list_item":
-				var listItemText []string
-				for _, rt := range row.BulletedListItem.RichText {
-					var p string = rt.PlainText
-					applyBold(&p, rt.Annotations.Bold)
-					applyItalic(&p, rt.Annotations.Italic)
-					applyStrikethrough(&p, rt.Annotations.Strikethrough)
-					applyUnderline(&p, rt.Annotations.Underline)
-					applyCode(&p, rt.Annotations.Code)
-					listItemText = append(listItemText, p)
-				}
-				// if previous block was also a bulleted list item, continue the list
-				if strings.HasSuffix(content.String(), "</li>\n") {
-					content.WriteString("<li>" + strings.Join(listItemText, " ") + "</li>\n")
-				} else {
-					content.WriteString("<ul>\n<li>" + strings.Join(listItemText, " ") + "</li>\n")
-				}
-			default:
-				// Handle other block types as needed
+		rd := newRenderer(r.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"content": rd.renderBlocks(blocksResp.Results),
+		})
+	})
+
+	// comments are only open on projects, not on any page the integration
+	// can see
+	isProject := func(ctx context.Context, pageId string) (bool, error) {
+		pageResp, err := notion.Page(pageId).FetchContext(ctx, client)
+		if err != nil {
+			if notion.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return sameID(pageResp.Parent.DataSourceID, cfg.projectsDatasourceId), nil
+	}
+
+	mux.HandleFunc("GET /api/v1/comments/{pageId}", func(w http.ResponseWriter, r *http.Request) {
+		pageId := r.PathValue("pageId")
+		if ok, err := isProject(r.Context(), pageId); err != nil {
+			writeError(w, err)
+			return
+		} else if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		commentsReq := notion.Comments(pageId).All()
+		commentsResp, err := commentsReq.FetchContext(r.Context(), client)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		rd := newRenderer(r.Context())
+		comments := []map[string]any{}
+		for _, comment := range commentsResp.Results {
+			author := ""
+			if comment.DisplayName != nil {
+				author = comment.DisplayName.ResolvedName
+			} else if user, err := users.Resolve(r.Context(), comment.CreatedBy); err == nil {
+				author = user.Name
 			}
+			comments = append(comments, map[string]any{
+				"id":           comment.ID,
+				"discussionId": comment.DiscussionID,
+				"author":       author,
+				"createdTime":  comment.CreatedTime,
+				"content":      rd.renderRichText(comment.RichText),
+			})
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]any{
-			"content": content.String(),
+			"comments": comments,
 		})
 	})
 
-	mux.HandleFunc("/api/v1/experience", func(w http.ResponseWriter, r *http.Request) {
-		experienceReq := notion.Datasource(experienceDatasourceId).Query(map[string]any{
-			"property": "Hidden",
-			"checkbox": map[string]any{
-				"equals": false,
-			},
-		}, []map[string]any{
-			{
-				"property":  "Date",
-				"direction": "descending",
-			},
+	// anonymous feedback spends the shared Notion request budget, so each
+	// visitor gets a handful of comments every ten minutes
+	commentThrottle := newThrottle(5, 10*time.Minute)
+
+	mux.HandleFunc("POST /api/v1/comments/{pageId}", commentThrottle.limitHandler(func(w http.ResponseWriter, r *http.Request) {
+		pageId := r.PathValue("pageId")
+
+		var feedback struct {
+			Name    string `json:"name"`
+			Message string `json:"message"`
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, 16<<10)
+		if err := json.NewDecoder(r.Body).Decode(&feedback); err != nil {
+			http.Error(w, "invalid comment: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		feedback.Name = strings.TrimSpace(feedback.Name)
+		feedback.Message = strings.TrimSpace(feedback.Message)
+		if feedback.Message == "" || utf8.RuneCountInString(feedback.Message) > 2000 || utf8.RuneCountInString(feedback.Name) > 100 {
+			http.Error(w, "comment needs a message of at most 2000 characters and a name of at most 100", http.StatusBadRequest)
+			return
+		}
+		if feedback.Name == "" {
+			feedback.Name = "Visitor"
+		}
+
+		if ok, err := isProject(r.Context(), pageId); err != nil {
+			writeError(w, err)
+			return
+		} else if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		commentReq := notion.CreateComment(pageId, notion.NewRichText(feedback.Message)...).DisplayName(feedback.Name)
+		commentResp, err := commentReq.FetchContext(r.Context(), client)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{
+			"id": commentResp.ID,
 		})
-		experienceResp, err := experienceReq.Fetch(client)
+	}))
+
+	mux.HandleFunc("/api/v1/experience", func(w http.ResponseWriter, r *http.Request) {
+		experienceReq := notion.Datasource(cfg.experienceDatasourceId).Query(visible, notion.SortBy("Date").Desc()).All()
+		experienceResp, err := experienceReq.FetchContext(r.Context(), client)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
 
 		var experience []map[string]any
 		for _, row := range experienceResp.Results {
-			logo := row.Cover.GetURL()
-			title_row := row.Properties["Company"]
-			var title string
-			if len(title_row.Title) > 0 {
-				title = title_row.Title[0].PlainText
-			} else {
+			var job struct {
+				Company  string  `notion:"Company,required"`
+				Position string  `notion:"Position"`
+				Start    string  `notion:"Date"`
+				End      *string `notion:"Date,end"`
+			}
+			if err := notion.Unmarshal(&row, &job); err != nil {
+				slog.Warn("skipping experience", "page", row.ID, "err", err)
 				continue
 			}
-			position := row.Properties["Position"].RichText[0].PlainText
-			date := row.Properties["Date"].Date
 
 			experience = append(experience, map[string]any{
-				"company":  title,
-				"position": position,
-				"logoURL":  logo,
-				"start":    date.Start,
-				"end":      date.End,
-				"current":  date.End == nil,
+				"company":  job.Company,
+				"position": job.Position,
+				"logoURL":  row.Cover.GetURL(),
+				"start":    job.Start,
+				"end":      job.End,
+				"current":  job.End == nil,
 				"pageId":   row.ID,
 			})
 		}
@@ -247,41 +353,30 @@ func main() {
 	})
 
 	mux.HandleFunc("/api/v1/projects", func(w http.ResponseWriter, r *http.Request) {
-		projectsReq := notion.Datasource(projectsDatasourceId).Query(map[string]any{
-			"property": "Hidden",
-			"checkbox": map[string]any{
-				"equals": false,
-			},
-		}, []map[string]any{
-			{
-				"property":  "Date",
-				"direction": "descending",
-			},
-		})
-		projectsResp, err := projectsReq.Fetch(client)
+		projectsReq := notion.Datasource(cfg.projectsDatasourceId).Query(visible, notion.SortBy("Date").Desc()).All()
+		projectsResp, err := projectsReq.FetchContext(r.Context(), client)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
 
 		var projects []map[string]any
 		for _, row := range projectsResp.Results {
-			imageUrl := row.Cover.GetURL()
-			title_row := row.Properties["Name"]
-			var title string
-			if len(title_row.Title) > 0 {
-				title = title_row.Title[0].PlainText
-			} else {
+			var project struct {
+				Title string  `notion:"Name,required"`
+				URL   *string `notion:"Project URL"`
+				Date  string  `notion:"Date"`
+			}
+			if err := notion.Unmarshal(&row, &project); err != nil {
+				slog.Warn("skipping project", "page", row.ID, "err", err)
 				continue
 			}
-			projectUrl := row.Properties["Project URL"].Url
-			date := row.Properties["Date"].Date
 
 			projects = append(projects, map[string]any{
-				"title":           title,
-				"projectImageURL": imageUrl,
-				"projectURL":      projectUrl,
-				"date":            date.Start,
+				"title":           project.Title,
+				"projectImageURL": row.Cover.GetURL(),
+				"projectURL":      project.URL,
+				"date":            project.Date,
 			})
 		}
 
@@ -292,36 +387,25 @@ func main() {
 	})
 
 	mux.HandleFunc("/api/v1/affiliations", func(w http.ResponseWriter, r *http.Request) {
-		affiliationsReq := notion.Datasource(affiliationsDatasourceId).Query(map[string]any{
-			"property": "Hidden",
-			"checkbox": map[string]any{
-				"equals": false,
-			},
-		}, []map[string]any{
-			{
-				"property":  "Display Order",
-				"direction": "ascending",
-			},
-		})
-		affiliationsResp, err := affiliationsReq.Fetch(client)
+		affiliationsReq := notion.Datasource(cfg.affiliationsDatasourceId).Query(visible, notion.SortBy("Display Order")).All()
+		affiliationsResp, err := affiliationsReq.FetchContext(r.Context(), client)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, err)
 			return
 		}
 
 		var affiliations []map[string]any
 		for _, row := range affiliationsResp.Results {
-			logo := row.Cover.GetURL()
-			title_row := row.Properties["Name"]
-			var title string
-			if len(title_row.Title) > 0 {
-				title = title_row.Title[0].PlainText
-			} else {
+			var affiliation struct {
+				Title string `notion:"Name,required"`
+			}
+			if err := notion.Unmarshal(&row, &affiliation); err != nil {
+				slog.Warn("skipping affiliation", "page", row.ID, "err", err)
 				continue
 			}
 			affiliations = append(affiliations, map[string]any{
-				"title":   title,
-				"logoURL": logo,
+				"title":   affiliation.Title,
+				"logoURL": row.Cover.GetURL(),
 			})
 		}
 
@@ -331,20 +415,160 @@ func main() {
 		})
 	})
 
+	// schemas only change when Notion sends data_source.schema_updated
+	schemas := &schemaCache{client: client}
+
+	mux.HandleFunc("/api/v1/options/{datasource}/{property}", func(w http.ResponseWriter, r *http.Request) {
+		datasourceId, ok := datasources[r.PathValue("datasource")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		schema, err := schemas.get(r.Context(), datasourceId)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		prop, ok := schema[r.PathValue("property")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		options := []map[string]any{}
+		for _, option := range prop.Options() {
+			options = append(options, map[string]any{
+				"name":  option.Name,
+				"color": option.Color,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"options": options,
+		})
+	})
+
+	// NOTION_WEBHOOK_TOKEN is only known after Notion's handshake. Anyone can
+	// send one, so until the token is set every attempt is logged with where
+	// it came from, and the token only with NOTION_WEBHOOK_LOG_TOKEN
+	webhook := notion.NewWebhook(cfg.webhookToken).
+		OnVerification(func(r *http.Request, token string) {
+			if cfg.webhookToken != "" {
+				return
+			}
+			attrs := []any{"remote_addr", r.RemoteAddr}
+			if cfg.logWebhookToken {
+				attrs = append(attrs, "verification_token", token)
+			}
+			slog.WarnContext(r.Context(), "notion webhook verification received; set NOTION_WEBHOOK_TOKEN to accept events", attrs...)
+		}).
+		On(notion.AnyEvent, func(ctx context.Context, event *notion.WebhookEvent) error {
+			slog.InfoContext(ctx, "notion webhook event", "type", event.Type, "entity_id", event.Entity.ID, "event_id", event.ID)
+			return nil
+		}).
+		On(notion.EventDataSourceSchemaUpdated, func(ctx context.Context, event *notion.WebhookEvent) error {
+			schemas.forget(event.Entity.ID)
+			for datasourceId, expected := range cfg.schemas() {
+				if sameID(datasourceId, event.Entity.ID) {
+					validateSchema(client, datasourceId, expected)
+				}
+			}
+			return nil
+		})
+	// moved or deleted pages may no longer have the cached public URL
+	for _, eventType := range []notion.EventType{notion.EventPageMoved, notion.EventPageDeleted, notion.EventPageUndeleted} {
+		webhook.On(eventType, func(ctx context.Context, event *notion.WebhookEvent) error {
+			mentionLinks.forget(event.Entity.ID)
+			return nil
+		})
+	}
+	mux.Handle("/api/v1/webhooks/notion", webhook)
+
 	mux.Handle("/", frontend.SvelteKitHandler())
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	return mux
+}
+
+// schemaCache keeps data source schemas between requests until forgotten.
+type schemaCache struct {
+	client *notion.Client
+	cache  sync.Map
+}
+
+func (sc *schemaCache) get(ctx context.Context, datasourceId string) (map[string]notion.PropertySchema, error) {
+	if schema, ok := sc.cache.Load(datasourceId); ok {
+		return schema.(map[string]notion.PropertySchema), nil
 	}
+	dsResp, err := notion.Datasource(datasourceId).FetchContext(ctx, sc.client)
+	if err != nil {
+		return nil, err
+	}
+	sc.cache.Store(datasourceId, dsResp.Properties)
+	return dsResp.Properties, nil
+}
 
-	server := &http.Server{
-		Addr:    ":" + port,
-		Handler: CorsMiddleware(mux),
+// forget drops a cached schema so it is fetched again.
+func (sc *schemaCache) forget(datasourceId string) {
+	sc.cache.Range(func(key, _ any) bool {
+		if sameID(key.(string), datasourceId) {
+			sc.cache.Delete(key)
+		}
+		return true
+	})
+}
+
+// datasourceId reads <PREFIX>_DATASOURCE_ID, or failing that resolves the data
+// source of the database in <PREFIX>_DATABASE_ID. Databases with several
+// data sources need <PREFIX>_DATASOURCE_NAME to pick one.
+func datasourceId(client *notion.Client, prefix string) string {
+	if id := os.Getenv(prefix + "_DATASOURCE_ID"); id != "" {
+		return id
 	}
 
-	fmt.Println("Starting server on :8080")
-	server.ListenAndServe()
+	databaseId := os.Getenv(prefix + "_DATABASE_ID")
+	if databaseId == "" {
+		panic(prefix + "_DATASOURCE_ID or " + prefix + "_DATABASE_ID not set")
+	}
+	dbResp, err := notion.Database(databaseId).Fetch(client)
+	if err != nil {
+		panic(prefix + "_DATABASE_ID: " + err.Error())
+	}
+	id, err := dbResp.Datasource(os.Getenv(prefix + "_DATASOURCE_NAME"))
+	if err != nil {
+		panic(prefix + "_DATABASE_ID: " + err.Error())
+	}
+	slog.Info("resolved data source", "database_id", databaseId, "datasource_id", id)
+	return id
+}
+
+// validateSchema warns at startup about datasource columns the handlers rely
+// on that are missing or have changed type, rather than failing on the first
+// request.
+func validateSchema(client *notion.Client, datasourceId string, expected map[string]string) {
+	dsResp, err := notion.Datasource(datasourceId).Fetch(client)
+	if err != nil {
+		slog.Warn("could not check data source schema", "datasource_id", datasourceId, "error", err)
+		return
+	}
+	if err := dsResp.Validate(expected); err != nil {
+		slog.Warn("data source schema mismatch", "datasource_id", datasourceId, "error", err)
+	}
+}
+
+// newLogger builds the process logger from LOG_FORMAT ("json" or "text") and
+// LOG_LEVEL ("debug", "info", "warn" or "error"), defaulting to text at info.
+func newLogger(format string, level string) *slog.Logger {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		lvl = slog.LevelInfo
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	if strings.EqualFold(format, "json") {
+		return slog.New(slog.NewJSONHandler(os.Stdout, opts))
+	}
+	return slog.New(slog.NewTextHandler(os.Stdout, opts))
 }
 
 func CorsMiddleware(next http.Handler) http.Handler {
@@ -360,32 +584,27 @@ func CorsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func applyBold(text *string, bold bool) {
-	if bold {
-		*text = "<strong>" + *text + "</strong>"
-	}
+// sameID compares Notion IDs, which appear both with and without dashes.
+func sameID(a string, b string) bool {
+	return strings.ReplaceAll(a, "-", "") == strings.ReplaceAll(b, "-", "")
 }
 
-func applyItalic(text *string, italic bool) {
-	if italic {
-		*text = "<em>" + *text + "</em>"
-	}
-}
-
-func applyUnderline(text *string, underline bool) {
-	if underline {
-		*text = "<u>" + *text + "</u>"
-	}
-}
-
-func applyStrikethrough(text *string, strikethrough bool) {
-	if strikethrough {
-		*text = "<s>" + *text + "</s>"
-	}
-}
-
-func applyCode(text *string, code bool) {
-	if code {
-		*text = "<code>" + *text + "</code>"
+// writeError maps notion errors onto the status the visitor should see.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case notion.IsNotFound(err):
+		status = http.StatusNotFound
+	case notion.IsValidation(err):
+		status = http.StatusBadRequest
+	case notion.IsRateLimited(err):
+		status = http.StatusServiceUnavailable
+		var apiErr *notion.APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(apiErr.RetryAfter.Seconds()))))
+		}
+	case notion.IsUnauthorized(err), notion.IsServerError(err):
+		status = http.StatusBadGateway
 	}
+	http.Error(w, err.Error(), status)
 }
@@ -0,0 +1,503 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danecwalker/portfolio/internal/notion"
+	"github.com/danecwalker/portfolio/internal/notion/notiontest"
+)
+
+var testConfig = config{
+	profilePageId:            "profile",
+	linksDatasourceId:        "links",
+	experienceDatasourceId:   "experience",
+	projectsDatasourceId:     "projects",
+	affiliationsDatasourceId: "affiliations",
+	webhookToken:             "secret_webhook",
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// newTestMux serves the handlers from a notiontest fake holding a small
+// portfolio workspace.
+func newTestMux(t *testing.T) (*notiontest.Server, http.Handler) {
+	t.Helper()
+	srv := notiontest.NewServer()
+	t.Cleanup(srv.Close)
+
+	row := func(id string, datasourceId string, props map[string]notion.Property) notion.PageResponse {
+		return notion.PageResponse{
+			ID:         id,
+			Parent:     notion.PageParent{Type: "data_source_id", DataSourceID: datasourceId},
+			Properties: props,
+		}
+	}
+
+	srv.AddPage(notion.PageResponse{
+		ID:         "profile",
+		Cover:      notion.ExternalFile("", "https://example.com/me.png"),
+		Properties: map[string]notion.Property{"title": notion.TitleValue("Profile")},
+	})
+
+	srv.AddDatasource(notion.DatasourceGetResponse{ID: "links"})
+	srv.AddPage(
+		row("link-1", "links", map[string]notion.Property{
+			"Name":          notion.TitleValue("GitHub"),
+			"Website URL":   notion.URLValue("https://github.com/example"),
+			"File":          notion.FilesValue(),
+			"Hidden":        notion.CheckboxValue(false),
+			"Display Order": notion.NumberValue(2),
+		}),
+		row("link-2", "links", map[string]notion.Property{
+			"Name":          notion.TitleValue("Resume"),
+			"Website URL":   {Type: "url"},
+			"File":          notion.FilesValue(notion.ExternalFile("cv.pdf", "https://example.com/cv.pdf")),
+			"Hidden":        notion.CheckboxValue(false),
+			"Display Order": notion.NumberValue(1),
+		}),
+		row("link-3", "links", map[string]notion.Property{
+			"Name":          notion.TitleValue("Secret"),
+			"Website URL":   notion.URLValue("https://example.com/secret"),
+			"File":          notion.FilesValue(),
+			"Hidden":        notion.CheckboxValue(true),
+			"Display Order": notion.NumberValue(0),
+		}),
+		row("link-4", "links", map[string]notion.Property{
+			"Name":          notion.TitleValue(""),
+			"Website URL":   notion.URLValue("https://example.com/untitled"),
+			"File":          notion.FilesValue(),
+			"Hidden":        notion.CheckboxValue(false),
+			"Display Order": notion.NumberValue(3),
+		}),
+	)
+
+	srv.AddDatasource(notion.DatasourceGetResponse{ID: "experience"})
+	srv.AddPage(
+		row("exp-1", "experience", map[string]notion.Property{
+			"Company":  notion.TitleValue("Acme"),
+			"Position": notion.RichTextValue("Engineer"),
+			"Date":     notion.DateRangeValue(date(2020, 1, 1), date(2022, 6, 30)),
+			"Hidden":   notion.CheckboxValue(false),
+		}),
+		row("exp-2", "experience", map[string]notion.Property{
+			"Company":  notion.TitleValue("Globex"),
+			"Position": notion.RichTextValue("Lead"),
+			"Date":     notion.DateValue(date(2022, 7, 1)),
+			"Hidden":   notion.CheckboxValue(false),
+		}),
+	)
+
+	srv.AddDatasource(notion.DatasourceGetResponse{
+		ID: "projects",
+		Properties: map[string]notion.PropertySchema{
+			"Tags": {Name: "Tags", Type: "multi_select", MultiSelect: &notion.OptionsSchema{Options: []notion.Select{{Name: "go", Color: "blue"}}}},
+		},
+	})
+	project := row("project-1", "projects", map[string]notion.Property{
+		"Name":        notion.TitleValue("Portfolio"),
+		"Project URL": notion.URLValue("https://example.com"),
+		"Date":        notion.DateValue(date(2024, 5, 1)),
+		"Hidden":      notion.CheckboxValue(false),
+	})
+	project.Cover = notion.ExternalFile("", "https://example.com/p.png")
+	srv.AddPage(project, row("project-2", "projects", map[string]notion.Property{
+		"Name":        notion.TitleValue("Draft"),
+		"Project URL": {Type: "url"},
+		"Date":        notion.DateValue(date(2025, 1, 1)),
+		"Hidden":      notion.CheckboxValue(true),
+	}))
+	srv.AddBlocks("project-1",
+		notion.BlockGetResponse{Type: "paragraph", Paragraph: &notion.Paragraph{RichText: notion.NewRichText("Hello <world>")}},
+		notion.BlockGetResponse{ID: "toggle-1", Type: "toggle", Toggle: &notion.Paragraph{RichText: notion.NewRichText("More")}},
+	)
+	srv.AddBlocks("toggle-1", notion.BlockGetResponse{Type: "paragraph", Paragraph: &notion.Paragraph{RichText: notion.NewRichText("inside")}})
+	srv.AddUsers(notion.User{Object: "user", ID: "user-grace", Type: "person", Name: "Grace"})
+	srv.AddComments(
+		notion.Comment{
+			Parent:      notion.PageParent{Type: "page_id", PageID: "project-1"},
+			RichText:    notion.NewRichText("Nice work"),
+			DisplayName: &notion.CommentDisplayName{Type: "custom", ResolvedName: "Ada"},
+		},
+		notion.Comment{
+			Parent:    notion.PageParent{Type: "page_id", PageID: "project-1"},
+			RichText:  notion.NewRichText("Thanks"),
+			CreatedBy: notion.User{Object: "user", ID: "user-grace"},
+		},
+	)
+
+	srv.AddDatasource(notion.DatasourceGetResponse{ID: "affiliations"})
+	srv.AddPage(row("aff-1", "affiliations", map[string]notion.Property{
+		"Name":          notion.TitleValue("University"),
+		"Hidden":        notion.CheckboxValue(false),
+		"Display Order": notion.NumberValue(1),
+	}))
+
+	client := srv.Client(notion.WithLogger(slog.New(slog.DiscardHandler)))
+	return srv, newMux(client, testConfig)
+}
+
+// normalize re-encodes a JSON body without HTML escaping so expected
+// fragments can be written as they read.
+func normalize(t *testing.T, body string) string {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(body), &v); err != nil {
+		return body
+	}
+	var out strings.Builder
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+	return strings.TrimSpace(out.String())
+}
+
+func TestMux(t *testing.T) {
+	_, mux := newTestMux(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		// want is the whole JSON response, contains parts of it
+		want     string
+		contains []string
+	}{
+		{
+			name:       "profile",
+			path:       "/api/v1/profile",
+			wantStatus: http.StatusOK,
+			want:       `{"profileImageUrl":"https://example.com/me.png"}`,
+		},
+		{
+			name:       "links skip hidden and untitled rows",
+			path:       "/api/v1/links",
+			wantStatus: http.StatusOK,
+			want: `{"links":[
+				{"title":"Resume","type":"file","url":"https://example.com/cv.pdf"},
+				{"title":"GitHub","type":"link","url":"https://github.com/example"}
+			]}`,
+		},
+		{
+			name:       "experience newest first",
+			path:       "/api/v1/experience",
+			wantStatus: http.StatusOK,
+			want: `{"experience":[
+				{"company":"Globex","position":"Lead","logoURL":"","start":"2022-07-01","end":null,"current":true,"pageId":"exp-2"},
+				{"company":"Acme","position":"Engineer","logoURL":"","start":"2020-01-01","end":"2022-06-30","current":false,"pageId":"exp-1"}
+			]}`,
+		},
+		{
+			name:       "projects",
+			path:       "/api/v1/projects",
+			wantStatus: http.StatusOK,
+			want:       `{"projects":[{"title":"Portfolio","projectImageURL":"https://example.com/p.png","projectURL":"https://example.com","date":"2024-05-01"}]}`,
+		},
+		{
+			name:       "affiliations",
+			path:       "/api/v1/affiliations",
+			wantStatus: http.StatusOK,
+			want:       `{"affiliations":[{"title":"University","logoURL":""}]}`,
+		},
+		{
+			name:       "content",
+			path:       "/api/v1/content/project-1",
+			wantStatus: http.StatusOK,
+			want:       `{"content":"<p>Hello &lt;world&gt;</p>\n<details><summary>More</summary>\n\n<p>inside</p>\n</details>\n"}`,
+		},
+		{
+			name:       "content of a missing page",
+			path:       "/api/v1/content/missing",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "options",
+			path:       "/api/v1/options/projects/Tags",
+			wantStatus: http.StatusOK,
+			want:       `{"options":[{"name":"go","color":"blue"}]}`,
+		},
+		{
+			name:       "options of an unknown data source",
+			path:       "/api/v1/options/secrets/Tags",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "options of an unknown property",
+			path:       "/api/v1/options/projects/Missing",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "comments",
+			path:       "/api/v1/comments/project-1",
+			wantStatus: http.StatusOK,
+			contains:   []string{`"author":"Ada"`, `"content":"Nice work"`, `"author":"Grace"`, `"content":"Thanks"`},
+		},
+		{
+			name:       "comments of a page that is not a project",
+			path:       "/api/v1/comments/profile",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "comments of a missing page",
+			path:       "/api/v1/comments/missing",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "post comment",
+			method:     http.MethodPost,
+			path:       "/api/v1/comments/project-1",
+			body:       `{"name":"Bob","message":"Great site"}`,
+			wantStatus: http.StatusCreated,
+			contains:   []string{`"id":"`},
+		},
+		{
+			name:       "posted comment is listed",
+			path:       "/api/v1/comments/project-1",
+			wantStatus: http.StatusOK,
+			contains:   []string{`"author":"Bob"`, `"content":"Great site"`},
+		},
+		{
+			name:       "post comment on a page that is not a project",
+			method:     http.MethodPost,
+			path:       "/api/v1/comments/profile",
+			body:       `{"message":"hello"}`,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "post empty comment",
+			method:     http.MethodPost,
+			path:       "/api/v1/comments/project-1",
+			body:       `{"name":"Bob","message":"   "}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "post comment that is too long",
+			method:     http.MethodPost,
+			path:       "/api/v1/comments/project-1",
+			body:       `{"message":"` + strings.Repeat("é", 2001) + `"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tt.path, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			got := normalize(t, rec.Body.String())
+			if tt.want != "" {
+				var gotJSON, wantJSON any
+				json.Unmarshal(rec.Body.Bytes(), &gotJSON)
+				if err := json.Unmarshal([]byte(tt.want), &wantJSON); err != nil {
+					t.Fatalf("bad want: %v", err)
+				}
+				if !reflect.DeepEqual(gotJSON, wantJSON) {
+					t.Errorf("got  %s\nwant %s", got, normalize(t, tt.want))
+				}
+			}
+			for _, part := range tt.contains {
+				if !strings.Contains(got, part) {
+					t.Errorf("response %s does not contain %s", got, part)
+				}
+			}
+		})
+	}
+}
+
+func TestMuxNotionErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		failure        *notion.APIError
+		wantStatus     int
+		wantRetryAfter string
+	}{
+		{
+			name:           "rate limited",
+			failure:        &notion.APIError{Status: http.StatusTooManyRequests, Code: notion.ErrCodeRateLimited, Message: "slow down", RetryAfter: 3 * time.Second},
+			wantStatus:     http.StatusServiceUnavailable,
+			wantRetryAfter: "3",
+		},
+		{
+			name:       "unauthorized",
+			failure:    &notion.APIError{Status: http.StatusUnauthorized, Code: notion.ErrCodeUnauthorized, Message: "bad token"},
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name:       "server error",
+			failure:    &notion.APIError{Status: http.StatusInternalServerError, Code: notion.ErrCodeInternalServer, Message: "oops"},
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name:       "validation",
+			failure:    &notion.APIError{Status: http.StatusBadRequest, Code: notion.ErrCodeValidation, Message: "bad filter"},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, mux := newTestMux(t)
+			srv.Fail("/v1/data_sources/", tt.failure)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/links", nil))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestMuxCommentThrottle(t *testing.T) {
+	_, mux := newTestMux(t)
+
+	post := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/project-1", strings.NewReader(`{"message":"hi"}`))
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := range 5 {
+		if rec := post("192.0.2.1:1000"); rec.Code != http.StatusCreated {
+			t.Fatalf("comment %d: status = %d", i+1, rec.Code)
+		}
+	}
+	rec := post("192.0.2.1:2000")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("sixth comment: status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("missing Retry-After")
+	}
+	if rec := post("192.0.2.2:1000"); rec.Code != http.StatusCreated {
+		t.Errorf("other visitor: status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+}
+
+// signWebhook returns the X-Notion-Signature Notion sends with body.
+func signWebhook(body string) string {
+	mac := hmac.New(sha256.New, []byte(testConfig.webhookToken))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestMuxWebhook(t *testing.T) {
+	_, mux := newTestMux(t)
+	event := `{"id":"evt-1","type":"page.moved","entity":{"id":"project-1","type":"page"}}`
+
+	tests := []struct {
+		name       string
+		signature  string
+		wantStatus int
+	}{
+		{name: "signed", signature: signWebhook(event), wantStatus: http.StatusOK},
+		{name: "bad signature", signature: "sha256=00", wantStatus: http.StatusUnauthorized},
+		{name: "unsigned", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/notion", strings.NewReader(event))
+			if tt.signature != "" {
+				req.Header.Set("X-Notion-Signature", tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	t.Run("handshakes before the token is set", func(t *testing.T) {
+		var logs bytes.Buffer
+		defer slog.SetDefault(slog.Default())
+		slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+
+		cfg := testConfig
+		cfg.webhookToken = ""
+		cfg.logWebhookToken = true
+		srv := notiontest.NewServer()
+		defer srv.Close()
+		mux := newMux(srv.Client(notion.WithLogger(slog.New(slog.DiscardHandler))), cfg)
+
+		// a stranger's handshake must not hide the one Notion sends after it
+		for _, handshake := range []struct{ token, remoteAddr string }{
+			{token: "secret_fake", remoteAddr: "203.0.113.9:4000"},
+			{token: "secret_real", remoteAddr: "198.51.100.7:5000"},
+		} {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/notion", strings.NewReader(`{"verification_token":"`+handshake.token+`"}`))
+			req.RemoteAddr = handshake.remoteAddr
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Errorf("handshake status = %d, want %d", rec.Code, http.StatusOK)
+			}
+			for _, want := range []string{"verification_token=" + handshake.token, "remote_addr=" + handshake.remoteAddr} {
+				if !strings.Contains(logs.String(), want) {
+					t.Errorf("logs are missing %s:\n%s", want, logs.String())
+				}
+			}
+		}
+	})
+}
+
+func TestMuxOptionsCache(t *testing.T) {
+	srv, mux := newTestMux(t)
+	options := func() string {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/options/projects/Tags", nil))
+		return normalize(t, rec.Body.String())
+	}
+	before := normalize(t, `{"options":[{"name":"go","color":"blue"}]}`)
+	after := normalize(t, `{"options":[{"name":"go","color":"blue"},{"name":"svelte","color":"orange"}]}`)
+
+	if got := options(); got != before {
+		t.Fatalf("options = %s, want %s", got, before)
+	}
+	srv.AddDatasource(notion.DatasourceGetResponse{
+		ID: "projects",
+		Properties: map[string]notion.PropertySchema{
+			"Tags": {Name: "Tags", Type: "multi_select", MultiSelect: &notion.OptionsSchema{Options: []notion.Select{{Name: "go", Color: "blue"}, {Name: "svelte", Color: "orange"}}}},
+		},
+	})
+	if got := options(); got != before {
+		t.Errorf("options before the webhook = %s, want the cached %s", got, before)
+	}
+
+	event := `{"id":"evt-2","type":"data_source.schema_updated","entity":{"id":"projects","type":"data_source"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/notion", strings.NewReader(event))
+	req.Header.Set("X-Notion-Signature", signWebhook(event))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("webhook status = %d", rec.Code)
+	}
+	if got := options(); got != after {
+		t.Errorf("options after the webhook = %s, want %s", got, after)
+	}
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"context"
+	"html"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danecwalker/portfolio/internal/notion"
+)
+
+// renderer turns Notion content into HTML for the frontend.
+type renderer struct {
+	// pageURL maps a mentioned page to a link on the site, or "" to render
+	// the mention as plain text.
+	pageURL func(pageId string) string
+	// userName returns the display name of a mentioned user, or "" to keep
+	// the mention's plain text.
+	userName func(user notion.User) string
+}
+
+// pageLinks resolves mentioned pages to URLs visitors can open: the site
+// root for the profile page, otherwise the page's public URL if it has been
+// published to the web. Lookups are cached for the life of the process.
+type pageLinks struct {
+	client        *notion.Client
+	profilePageId string
+	cache         sync.Map
+}
+
+func (pl *pageLinks) resolver(ctx context.Context) func(string) string {
+	return func(pageId string) string {
+		if sameID(pageId, pl.profilePageId) {
+			return "/"
+		}
+		if url, ok := pl.cache.Load(pageId); ok {
+			return url.(string)
+		}
+
+		page, err := notion.Page(pageId).FetchContext(ctx, pl.client)
+		if err != nil {
+			if notion.IsNotFound(err) {
+				pl.cache.Store(pageId, "")
+			}
+			return ""
+		}
+		pl.cache.Store(pageId, page.PublicURL)
+		return page.PublicURL
+	}
+}
+
+// forget drops a cached page so its URL is looked up again.
+func (pl *pageLinks) forget(pageId string) {
+	pl.cache.Range(func(key, _ any) bool {
+		if sameID(key.(string), pageId) {
+			pl.cache.Delete(key)
+		}
+		return true
+	})
+}
+
+// renderBlocks converts page content into the HTML fragment the frontend
+// shows in experience cards. Consecutive list items are grouped into a
+// single <ul> or <ol>.
+func (r *renderer) renderBlocks(blocks []notion.BlockResponse) string {
+	var content strings.Builder
+	openList := ""
+	for _, block := range blocks {
+		list := listTag(block.Type)
+		if list != openList {
+			if openList != "" {
+				content.WriteString("</" + openList + ">\n")
+			}
+			if list != "" {
+				content.WriteString("<" + list + ">\n")
+			}
+			openList = list
+		}
+		r.renderBlock(&content, block)
+	}
+	if openList != "" {
+		content.WriteString("</" + openList + ">\n")
+	}
+	return content.String()
+}
+
+func listTag(blockType string) string {
+	switch blockType {
+	case "bulleted_list_item", "to_do":
+		return "ul"
+	case "numbered_list_item":
+		return "ol"
+	default:
+		return ""
+	}
+}
+
+func (r *renderer) renderBlock(content *strings.Builder, block notion.BlockResponse) {
+	switch block.Type {
+	case "paragraph":
+		content.WriteString("<p>" + r.renderRichText(block.Paragraph.RichText) + "</p>\n" + r.renderIndented(block))
+	case "heading_1":
+		r.renderHeading(content, "h1", block.Heading1, block)
+	case "heading_2":
+		r.renderHeading(content, "h2", block.Heading2, block)
+	case "heading_3":
+		r.renderHeading(content, "h3", block.Heading3, block)
+	case "bulleted_list_item":
+		content.WriteString("<li>" + r.renderRichText(block.BulletedListItem.RichText) + r.renderChildren(block) + "</li>\n")
+	case "numbered_list_item":
+		content.WriteString("<li>" + r.renderRichText(block.NumberedListItem.RichText) + r.renderChildren(block) + "</li>\n")
+	case "to_do":
+		checked := ""
+		if block.ToDo.Checked {
+			checked = " checked"
+		}
+		content.WriteString(`<li class="todo"><input type="checkbox" disabled` + checked + "> " + r.renderRichText(block.ToDo.RichText) + r.renderChildren(block) + "</li>\n")
+	case "quote":
+		content.WriteString("<blockquote>" + r.renderRichText(block.Quote.RichText) + r.renderChildren(block) + "</blockquote>\n")
+	case "toggle":
+		content.WriteString("<details><summary>" + r.renderRichText(block.Toggle.RichText) + "</summary>\n" + r.renderChildren(block) + "</details>\n")
+	case "callout":
+		icon := ""
+		if block.Callout.Icon != nil && block.Callout.Icon.Type == "emoji" {
+			icon = `<span class="callout-icon">` + html.EscapeString(block.Callout.Icon.Emoji) + "</span> "
+		}
+		content.WriteString(`<aside class="callout">` + icon + r.renderRichText(block.Callout.RichText) + r.renderChildren(block) + "</aside>\n")
+	case "code":
+		var code strings.Builder
+		for _, rt := range block.Code.RichText {
+			code.WriteString(html.EscapeString(rt.PlainText))
+		}
+		content.WriteString(`<pre><code class="language-` + html.EscapeString(block.Code.Language) + `">` + code.String() + "</code></pre>\n")
+	case "equation":
+		content.WriteString(`<div class="equation">` + html.EscapeString(block.Equation.Expression) + "</div>\n")
+	case "divider":
+		content.WriteString("<hr>\n")
+	case "image":
+		content.WriteString(`<figure><img src="` + html.EscapeString(block.Image.GetURL()) + `" alt="` + html.EscapeString(notion.PlainText(block.Image.Caption)) + `">`)
+		if len(block.Image.Caption) > 0 {
+			content.WriteString("<figcaption>" + r.renderRichText(block.Image.Caption) + "</figcaption>")
+		}
+		content.WriteString("</figure>\n")
+	case "video":
+		content.WriteString(`<video src="` + html.EscapeString(block.Video.GetURL()) + `" controls></video>` + "\n")
+	case "file", "pdf", "audio":
+		file := block.File
+		if block.Type == "pdf" {
+			file = block.PDF
+		} else if block.Type == "audio" {
+			file = block.Audio
+		}
+		name := file.Name
+		if name == "" {
+			name = notion.PlainText(file.Caption)
+		}
+		if name == "" {
+			name = file.GetURL()
+		}
+		link := html.EscapeString(name)
+		applyLink(&link, file.GetURL())
+		content.WriteString("<p>" + link + "</p>\n")
+	case "bookmark":
+		content.WriteString(renderLink(block.Bookmark.URL))
+	case "embed":
+		content.WriteString(renderLink(block.Embed.URL))
+	case "link_preview":
+		content.WriteString(renderLink(block.LinkPreview.URL))
+	case "column_list":
+		content.WriteString(`<div class="columns">` + "\n" + r.renderChildren(block) + "</div>\n")
+	case "column":
+		content.WriteString(`<div class="column">` + "\n" + r.renderChildren(block) + "</div>\n")
+	case "synced_block":
+		content.WriteString(r.renderChildren(block))
+	case "table":
+		r.renderTable(content, block)
+	default:
+		// Handle other block types as needed
+	}
+}
+
+// renderChildren renders nested blocks fetched by notion.BlockTree.
+func (r *renderer) renderChildren(block notion.BlockResponse) string {
+	if len(block.Children) == 0 {
+		return ""
+	}
+	return "\n" + r.renderBlocks(block.Children)
+}
+
+// renderIndented renders the children of blocks that can't contain other
+// blocks in HTML, such as paragraphs, as an indented group after the block.
+func (r *renderer) renderIndented(block notion.BlockResponse) string {
+	if len(block.Children) == 0 {
+		return ""
+	}
+	return `<div class="indent">` + r.renderChildren(block) + "</div>\n"
+}
+
+// renderHeading renders toggleable headings as a <details> whose summary is
+// the heading.
+func (r *renderer) renderHeading(content *strings.Builder, tag string, heading *notion.Heading, block notion.BlockResponse) {
+	text := "<" + tag + ">" + r.renderRichText(heading.RichText) + "</" + tag + ">"
+	if !heading.IsToggleable {
+		content.WriteString(text + "\n" + r.renderIndented(block))
+		return
+	}
+	content.WriteString("<details><summary>" + text + "</summary>\n" + r.renderChildren(block) + "</details>\n")
+}
+
+func (r *renderer) renderTable(content *strings.Builder, block notion.BlockResponse) {
+	content.WriteString("<table>\n")
+	for i, row := range block.Children {
+		if row.TableRow == nil {
+			continue
+		}
+		content.WriteString("<tr>")
+		for j, cell := range row.TableRow.Cells {
+			tag := "td"
+			if (i == 0 && block.Table.HasColumnHeader) || (j == 0 && block.Table.HasRowHeader) {
+				tag = "th"
+			}
+			content.WriteString("<" + tag + ">" + r.renderRichText(cell) + "</" + tag + ">")
+		}
+		content.WriteString("</tr>\n")
+	}
+	content.WriteString("</table>\n")
+}
+
+func renderLink(href string) string {
+	link := html.EscapeString(href)
+	applyLink(&link, href)
+	return "<p>" + link + "</p>\n"
+}
+
+// safeURL reports whether href may be used as a link on the site: http,
+// https and mailto URLs, and paths on the site itself. Anything else, such
+// as javascript: URLs, is rendered as plain text.
+func safeURL(href string) bool {
+	if strings.HasPrefix(href, "/") {
+		return !strings.HasPrefix(href, "//") && !strings.HasPrefix(href, "/\\")
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "mailto":
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *renderer) renderRichText(richText []notion.RichText) string {
+	var parts []string
+	for _, rt := range richText {
+		p, href := r.renderInline(rt)
+		applyBold(&p, rt.Annotations.Bold)
+		applyItalic(&p, rt.Annotations.Italic)
+		applyStrikethrough(&p, rt.Annotations.Strikethrough)
+		applyUnderline(&p, rt.Annotations.Underline)
+		applyCode(&p, rt.Annotations.Code)
+		applyLink(&p, href)
+		parts = append(parts, p)
+	}
+	return strings.Join(parts, "")
+}
+
+// renderInline returns the escaped HTML for a single rich text item and the
+// URL it should link to, if any.
+func (r *renderer) renderInline(rt notion.RichText) (string, string) {
+	text := html.EscapeString(rt.PlainText)
+	switch rt.Type {
+	case "equation":
+		if rt.Equation == nil {
+			return text, ""
+		}
+		return `<span class="equation">` + html.EscapeString(rt.Equation.Expression) + "</span>", ""
+	case "mention":
+		if rt.Mention == nil {
+			return text, ""
+		}
+		return r.renderMention(rt, text)
+	default:
+		if rt.Text != nil && rt.Text.Link != nil {
+			return text, rt.Text.Link.URL
+		}
+		if rt.Href != nil {
+			return text, *rt.Href
+		}
+		return text, ""
+	}
+}
+
+// renderMention falls back to the mention's plain text when its payload is
+// missing or its type is unknown.
+func (r *renderer) renderMention(rt notion.RichText, text string) (string, string) {
+	mention := rt.Mention
+	switch {
+	case mention.Type == "page" && mention.Page != nil:
+		if r.pageURL == nil {
+			return text, ""
+		}
+		return text, r.pageURL(mention.Page.ID)
+	case mention.Type == "date" && mention.Date != nil:
+		return `<time datetime="` + html.EscapeString(mention.Date.Start) + `">` + html.EscapeString(formatDate(mention.Date)) + "</time>", ""
+	case mention.Type == "user":
+		if r.userName != nil && mention.User != nil {
+			if name := r.userName(*mention.User); name != "" {
+				text = "@" + html.EscapeString(name)
+			}
+		}
+		return `<span class="mention">` + text + "</span>", ""
+	case mention.Type == "link_preview" && mention.LinkPreview != nil:
+		return text, mention.LinkPreview.URL
+	case mention.Type == "link_mention" && mention.LinkMention != nil:
+		return text, mention.LinkMention.Href
+	default:
+		// database and template mentions link to the Notion workspace,
+		// which visitors can't open
+		return text, ""
+	}
+}
+
+// formatDate renders a date mention the way Notion displays it.
+func formatDate(date *notion.Date) string {
+	text := formatDateValue(date.Start)
+	if date.End != nil {
+		text += " → " + formatDateValue(*date.End)
+	}
+	return text
+}
+
+func formatDateValue(value string) string {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t.Format("January 2, 2006")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Format("January 2, 2006 3:04 PM")
+	}
+	return value
+}
+
+func applyBold(text *string, bold bool) {
+	if bold {
+		*text = "<strong>" + *text + "</strong>"
+	}
+}
+
+func applyItalic(text *string, italic bool) {
+	if italic {
+		*text = "<em>" + *text + "</em>"
+	}
+}
+
+func applyUnderline(text *string, underline bool) {
+	if underline {
+		*text = "<u>" + *text + "</u>"
+	}
+}
+
+func applyStrikethrough(text *string, strikethrough bool) {
+	if strikethrough {
+		*text = "<s>" + *text + "</s>"
+	}
+}
+
+func applyLink(text *string, href string) {
+	if href != "" && safeURL(href) {
+		*text = `<a href="` + html.EscapeString(href) + `">` + *text + "</a>"
+	}
+}
+
+func applyCode(text *string, code bool) {
+	if code {
+		*text = "<code>" + *text + "</code>"
+	}
+}
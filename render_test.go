@@ -0,0 +1,280 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/danecwalker/portfolio/internal/notion"
+)
+
+func text(content string) notion.RichText {
+	return notion.RichText{Type: "text", Text: &notion.Text{Content: content}, PlainText: content}
+}
+
+func paragraph(content string, children ...notion.BlockResponse) notion.BlockResponse {
+	block := notion.BlockResponse{}
+	block.Type = "paragraph"
+	block.Paragraph = &notion.Paragraph{RichText: []notion.RichText{text(content)}}
+	block.Children = children
+	return block
+}
+
+func listItem(blockType string, content string) notion.BlockResponse {
+	block := notion.BlockResponse{}
+	block.Type = blockType
+	item := &notion.Paragraph{RichText: []notion.RichText{text(content)}}
+	if blockType == "numbered_list_item" {
+		block.NumberedListItem = item
+	} else {
+		block.BulletedListItem = item
+	}
+	return block
+}
+
+func heading(content string, toggleable bool, children ...notion.BlockResponse) notion.BlockResponse {
+	block := notion.BlockResponse{}
+	block.Type = "heading_2"
+	block.Heading2 = &notion.Heading{RichText: []notion.RichText{text(content)}, IsToggleable: toggleable}
+	block.Children = children
+	return block
+}
+
+func TestRenderBlocks(t *testing.T) {
+	toDo := notion.BlockResponse{}
+	toDo.Type = "to_do"
+	toDo.ToDo = &notion.ToDo{RichText: []notion.RichText{text("ship")}, Checked: true}
+
+	toggle := notion.BlockResponse{}
+	toggle.Type = "toggle"
+	toggle.Toggle = &notion.Paragraph{RichText: []notion.RichText{text("More")}}
+	toggle.Children = []notion.BlockResponse{paragraph("hidden")}
+
+	callout := notion.BlockResponse{}
+	callout.Type = "callout"
+	callout.Callout = &notion.Callout{RichText: []notion.RichText{text("note")}, Icon: &notion.FileObject{Type: "emoji", Emoji: "💡"}}
+
+	badIcon := notion.BlockResponse{}
+	badIcon.Type = "callout"
+	badIcon.Callout = &notion.Callout{RichText: []notion.RichText{text("note")}, Icon: &notion.FileObject{Type: "emoji", Emoji: "<img>"}}
+
+	code := notion.BlockResponse{}
+	code.Type = "code"
+	code.Code = &notion.Code{Language: "go", RichText: []notion.RichText{text("if a < b {}")}}
+
+	bookmark := notion.BlockResponse{}
+	bookmark.Type = "bookmark"
+	bookmark.Bookmark = &notion.Bookmark{URL: "https://example.com/?a=1&b=2"}
+
+	unsafeBookmark := notion.BlockResponse{}
+	unsafeBookmark.Type = "bookmark"
+	unsafeBookmark.Bookmark = &notion.Bookmark{URL: "javascript:alert(1)"}
+
+	tests := []struct {
+		name   string
+		blocks []notion.BlockResponse
+		want   string
+	}{
+		{
+			name:   "paragraph",
+			blocks: []notion.BlockResponse{paragraph("a <b> & c")},
+			want:   "<p>a &lt;b&gt; &amp; c</p>\n",
+		},
+		{
+			name:   "paragraph children are indented",
+			blocks: []notion.BlockResponse{paragraph("parent", paragraph("child"))},
+			want:   "<p>parent</p>\n<div class=\"indent\">\n<p>child</p>\n</div>\n",
+		},
+		{
+			name: "list items are grouped",
+			blocks: []notion.BlockResponse{
+				listItem("bulleted_list_item", "one"),
+				listItem("bulleted_list_item", "two"),
+				listItem("numbered_list_item", "first"),
+				paragraph("after"),
+			},
+			want: "<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n<ol>\n<li>first</li>\n</ol>\n<p>after</p>\n",
+		},
+		{
+			name:   "to do",
+			blocks: []notion.BlockResponse{toDo},
+			want:   "<ul>\n<li class=\"todo\"><input type=\"checkbox\" disabled checked> ship</li>\n</ul>\n",
+		},
+		{
+			name:   "heading",
+			blocks: []notion.BlockResponse{heading("Title", false)},
+			want:   "<h2>Title</h2>\n",
+		},
+		{
+			name:   "heading children are indented",
+			blocks: []notion.BlockResponse{heading("Title", false, paragraph("body"))},
+			want:   "<h2>Title</h2>\n<div class=\"indent\">\n<p>body</p>\n</div>\n",
+		},
+		{
+			name:   "toggleable heading",
+			blocks: []notion.BlockResponse{heading("Title", true, paragraph("body"))},
+			want:   "<details><summary><h2>Title</h2></summary>\n\n<p>body</p>\n</details>\n",
+		},
+		{
+			name:   "toggle",
+			blocks: []notion.BlockResponse{toggle},
+			want:   "<details><summary>More</summary>\n\n<p>hidden</p>\n</details>\n",
+		},
+		{
+			name:   "callout",
+			blocks: []notion.BlockResponse{callout},
+			want:   "<aside class=\"callout\"><span class=\"callout-icon\">💡</span> note</aside>\n",
+		},
+		{
+			name:   "callout icon is escaped",
+			blocks: []notion.BlockResponse{badIcon},
+			want:   "<aside class=\"callout\"><span class=\"callout-icon\">&lt;img&gt;</span> note</aside>\n",
+		},
+		{
+			name:   "code is escaped",
+			blocks: []notion.BlockResponse{code},
+			want:   "<pre><code class=\"language-go\">if a &lt; b {}</code></pre>\n",
+		},
+		{
+			name:   "bookmark",
+			blocks: []notion.BlockResponse{bookmark},
+			want:   "<p><a href=\"https://example.com/?a=1&amp;b=2\">https://example.com/?a=1&amp;b=2</a></p>\n",
+		},
+		{
+			name:   "unsafe bookmark is plain text",
+			blocks: []notion.BlockResponse{unsafeBookmark},
+			want:   "<p>javascript:alert(1)</p>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &renderer{}
+			if got := r.renderBlocks(tt.blocks); got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderRichText(t *testing.T) {
+	link := func(content string, url string) notion.RichText {
+		rt := text(content)
+		rt.Text.Link = &notion.Link{URL: url}
+		return rt
+	}
+	bold := text("bold")
+	bold.Annotations.Bold = true
+	end := "2024-02-01"
+
+	tests := []struct {
+		name     string
+		richText []notion.RichText
+		want     string
+	}{
+		{
+			name:     "annotations",
+			richText: []notion.RichText{bold},
+			want:     "<strong>bold</strong>",
+		},
+		{
+			name:     "segments are joined as written",
+			richText: []notion.RichText{text("Hello, "), bold, text("world.")},
+			want:     "Hello, <strong>bold</strong>world.",
+		},
+		{
+			name:     "link",
+			richText: []notion.RichText{link("site", "https://example.com/?a=1&b=2")},
+			want:     `<a href="https://example.com/?a=1&amp;b=2">site</a>`,
+		},
+		{
+			name:     "javascript link",
+			richText: []notion.RichText{link("click", "javascript:alert(1)")},
+			want:     "click",
+		},
+		{
+			name:     "protocol-relative link",
+			richText: []notion.RichText{link("away", "//evil.example")},
+			want:     "away",
+		},
+		{
+			name: "page mention",
+			richText: []notion.RichText{{
+				Type:      "mention",
+				PlainText: "About",
+				Mention:   &notion.Mention{Type: "page", Page: &notion.Reference{ID: "about"}},
+			}},
+			want: `<a href="/about">About</a>`,
+		},
+		{
+			name: "date mention",
+			richText: []notion.RichText{{
+				Type:      "mention",
+				PlainText: "2024-01-05",
+				Mention:   &notion.Mention{Type: "date", Date: &notion.Date{Start: "2024-01-05", End: &end}},
+			}},
+			want: `<time datetime="2024-01-05">January 5, 2024 → February 1, 2024</time>`,
+		},
+		{
+			name: "user mention",
+			richText: []notion.RichText{{
+				Type:      "mention",
+				PlainText: "@Anonymous",
+				Mention:   &notion.Mention{Type: "user", User: &notion.User{ID: "user-1"}},
+			}},
+			want: `<span class="mention">@Ada</span>`,
+		},
+		{
+			name: "mention without a payload",
+			richText: []notion.RichText{{
+				Type:      "mention",
+				PlainText: "Lost",
+				Mention:   &notion.Mention{Type: "page"},
+			}},
+			want: "Lost",
+		},
+		{
+			name:     "equation without a payload",
+			richText: []notion.RichText{{Type: "equation", PlainText: "x"}},
+			want:     "x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &renderer{
+				pageURL: func(pageId string) string { return "/" + pageId },
+				userName: func(user notion.User) string {
+					if user.ID == "user-1" {
+						return "Ada"
+					}
+					return ""
+				},
+			}
+			if got := r.renderRichText(tt.richText); got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSafeURL(t *testing.T) {
+	tests := []struct {
+		href string
+		want bool
+	}{
+		{href: "https://example.com", want: true},
+		{href: "HTTP://example.com", want: true},
+		{href: "mailto:me@example.com", want: true},
+		{href: "/projects", want: true},
+		{href: "//evil.example", want: false},
+		{href: `/\evil.example`, want: false},
+		{href: "javascript:alert(1)", want: false},
+		{href: "JavaScript:alert(1)", want: false},
+		{href: "data:text/html,hi", want: false},
+		{href: "relative/path", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.href, func(t *testing.T) {
+			if got := safeURL(tt.href); got != tt.want {
+				t.Errorf("safeURL(%q) = %v, want %v", tt.href, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// throttle limits how often each client IP may call an endpoint, allowing
+// limit calls in any window. IPs come from the connection, so behind a
+// proxy all visitors share one allowance.
+type throttle struct {
+	limit  int
+	window time.Duration
+
+	mu   sync.Mutex
+	hits map[string][]time.Time
+}
+
+func newThrottle(limit int, window time.Duration) *throttle {
+	return &throttle{
+		limit:  limit,
+		window: window,
+		hits:   map[string][]time.Time{},
+	}
+}
+
+// allow records a call from r's IP, reporting how long it must wait if it
+// is over the limit.
+func (t *throttle) allow(r *http.Request) (time.Duration, bool) {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if len(t.hits) > 4096 {
+		for other, hits := range t.hits {
+			if now.Sub(hits[len(hits)-1]) > t.window {
+				delete(t.hits, other)
+			}
+		}
+	}
+
+	hits := t.hits[ip]
+	for len(hits) > 0 && now.Sub(hits[0]) > t.window {
+		hits = hits[1:]
+	}
+	if len(hits) >= t.limit {
+		t.hits[ip] = hits
+		return t.window - now.Sub(hits[0]), false
+	}
+	t.hits[ip] = append(hits, now)
+	return 0, true
+}
+
+// limitHandler wraps next, answering 429 with Retry-After once an IP is over the
+// limit.
+func (t *throttle) limitHandler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if wait, ok := t.allow(r); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+			http.Error(w, "too many comments, try again later", http.StatusTooManyRequests)
+			return
+		}
+		next(w, r)
+	}
+}